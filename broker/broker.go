@@ -12,10 +12,15 @@ const (
 
 	KEY_TRACE_MSG_CARRIER = "trace_msg_carrier"
 	KEY_BROKER_MSG        = "msg"
+
+	// HEADER_MESSAGE_ID is the carrier header holding the message idempotency key
+	HEADER_MESSAGE_ID = "Msg-Id"
 )
 
 // Message structure
 type Message struct {
+	// ID is the idempotency key set by the publisher, if any
+	ID     string
 	Body   []byte
 	Extras map[string]interface{}
 }
@@ -26,6 +31,14 @@ type Handler interface {
 	Handle(ctx context.Context, m *Message) error
 }
 
+// HandlerFunc adapts a function to the Handler interface
+type HandlerFunc func(ctx context.Context, m *Message) error
+
+// Handle calls the function with the subscribed message
+func (f HandlerFunc) Handle(ctx context.Context, m *Message) error {
+	return f(ctx, m)
+}
+
 // Broker interface for adding new brokers
 type Broker interface {
 	component.Component
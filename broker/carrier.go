@@ -31,6 +31,11 @@ func NewTraceMsgCarrierFromBytes(tmBytes []byte) *TraceMsgCarrier {
 		return nil
 	}
 
+	// gob skips empty maps while encoding
+	if tm.Headers == nil {
+		tm.Headers = make(map[string]string)
+	}
+
 	return tm
 }
 
@@ -54,6 +59,20 @@ func (tm *TraceMsgCarrier) Keys() []string {
 	return keys
 }
 
+// MessageID returns the message idempotency key set by the publisher
+func (tm *TraceMsgCarrier) MessageID() string {
+	return tm.Get(HEADER_MESSAGE_ID)
+}
+
+// SetMessageID sets the message idempotency key
+func (tm *TraceMsgCarrier) SetMessageID(id string) {
+	if id == "" {
+		return
+	}
+
+	tm.Set(HEADER_MESSAGE_ID, id)
+}
+
 // Bytes converts the TraceMsgCarrier instance to bytes
 func (tm *TraceMsgCarrier) Bytes() ([]byte, error) {
 	var data bytes.Buffer
@@ -0,0 +1,56 @@
+package broker
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Marshaler is implemented by the messages encoding themselves.
+// The brokers publish the encoded payload as is, instead of the JSON encoding of the message.
+type Marshaler interface {
+	// MarshalPayload returns the encoded message
+	MarshalPayload() ([]byte, error)
+}
+
+// Marshal encodes the message published, using JSON unless it implements Marshaler
+func Marshal(message interface{}) ([]byte, error) {
+	if m, ok := message.(Marshaler); ok {
+		return m.MarshalPayload()
+	}
+
+	return json.Marshal(message)
+}
+
+// Payload is a message already encoded, published as is
+type Payload []byte
+
+// MarshalPayload returns the payload
+func (p Payload) MarshalPayload() ([]byte, error) {
+	return p, nil
+}
+
+// protoMessage publishes the protobuf message with the protobuf encoding
+type protoMessage struct {
+	proto.Message
+}
+
+// MarshalPayload returns the protobuf encoding of the message
+func (m protoMessage) MarshalPayload() ([]byte, error) {
+	return proto.Marshal(m.Message)
+}
+
+// Proto wraps the protobuf message to publish it with the protobuf encoding
+func Proto(m proto.Message) Marshaler {
+	return protoMessage{m}
+}
+
+// UnmarshalProto decodes the protobuf message from the body of the message received
+func UnmarshalProto(m *Message, v proto.Message) error {
+	if err := proto.Unmarshal(m.Body, v); err != nil {
+		return fmt.Errorf("unmarshalling error: %v", err)
+	}
+
+	return nil
+}
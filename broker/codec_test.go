@@ -0,0 +1,30 @@
+package broker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestMarshal(t *testing.T) {
+	payload, err := Marshal(map[string]string{"key": "value"})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"key":"value"}`, string(payload))
+
+	payload, err = Marshal(Payload("raw"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("raw"), payload)
+}
+
+func TestProto(t *testing.T) {
+	payload, err := Marshal(Proto(wrapperspb.String("event")))
+	require.NoError(t, err)
+
+	v := &wrapperspb.StringValue{}
+	require.NoError(t, UnmarshalProto(&Message{Body: payload}, v))
+	require.True(t, proto.Equal(wrapperspb.String("event"), v))
+
+	require.Error(t, UnmarshalProto(&Message{Body: []byte{0xff}}, v))
+}
@@ -0,0 +1,220 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/easeq/go-service/kvstore"
+	"github.com/easeq/go-service/logger"
+)
+
+const (
+	// DEFAULT_DEDUP_PREFIX is the kvstore key prefix used for processed message IDs
+	DEFAULT_DEDUP_PREFIX = "broker/dedup"
+	// DEFAULT_DEDUP_TTL is the time for which a processed message ID is remembered
+	DEFAULT_DEDUP_TTL = 24 * time.Hour
+	// DEFAULT_DEDUP_LEASE is the time for which a message being handled is claimed
+	DEFAULT_DEDUP_LEASE = 5 * time.Minute
+
+	// DEDUP_IN_PROGRESS is the value of the message IDs claimed by a subscriber handling the message
+	DEDUP_IN_PROGRESS = "in-progress"
+	// DEDUP_DONE is the value of the message IDs processed
+	DEDUP_DONE = "done"
+)
+
+// MessageIDSetter is implemented by the broker publishers that support
+// idempotency keys
+type MessageIDSetter interface {
+	// SetMessageID sets the idempotency key of the message being published
+	SetMessageID(id string)
+}
+
+// DeduplicatorSetter is implemented by the broker subscribers that support
+// consumer-side deduplication
+type DeduplicatorSetter interface {
+	// SetDeduplicator sets the deduplicator used by the subscription
+	SetDeduplicator(d Deduplicator)
+}
+
+// WithMessageID sets the idempotency key of the published message.
+// Messages published with the same ID are handled only once by the subscribers.
+func WithMessageID(id string) PublishOption {
+	return func(p Publisher) {
+		if s, ok := p.(MessageIDSetter); ok {
+			s.SetMessageID(id)
+		}
+	}
+}
+
+// WithDeduplicator enables consumer-side deduplication for the subscription
+func WithDeduplicator(d Deduplicator) SubscribeOption {
+	return func(s Subscriber) {
+		if ds, ok := s.(DeduplicatorSetter); ok {
+			ds.SetDeduplicator(d)
+		}
+	}
+}
+
+// Deduplicator keeps track of the message IDs claimed by the subscribers
+type Deduplicator interface {
+	// Claim atomically records the message with the given ID as in progress before it is handled.
+	// It returns false if the message is in progress or was already processed.
+	// The claim expires after a lease, so that the message is handled again
+	// if the subscriber stops while handling it.
+	Claim(ctx context.Context, topic string, id string) (bool, error)
+	// Complete records the message claimed as processed, once it's handled successfully
+	Complete(ctx context.Context, topic string, id string) error
+	// Release removes the claim of a message that failed to be handled,
+	// so that the redelivered message is handled again
+	Release(ctx context.Context, topic string, id string) error
+}
+
+// KVDeduplicatorOption to pass as arg while creating new kvstore deduplicator
+type KVDeduplicatorOption func(*KVDeduplicator)
+
+// KVDeduplicator is a Deduplicator backed by a kvstore.KVStore
+type KVDeduplicator struct {
+	store  kvstore.KVStore
+	prefix string
+	ttl    time.Duration
+	lease  time.Duration
+}
+
+// NewKVDeduplicator returns a new deduplicator that saves processed message IDs in the store
+func NewKVDeduplicator(store kvstore.KVStore, opts ...KVDeduplicatorOption) *KVDeduplicator {
+	d := &KVDeduplicator{
+		store:  store,
+		prefix: DEFAULT_DEDUP_PREFIX,
+		ttl:    DEFAULT_DEDUP_TTL,
+		lease:  DEFAULT_DEDUP_LEASE,
+	}
+
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	return d
+}
+
+// WithDedupPrefix sets the kvstore key prefix for the processed message IDs
+func WithDedupPrefix(prefix string) KVDeduplicatorOption {
+	return func(d *KVDeduplicator) {
+		d.prefix = prefix
+	}
+}
+
+// WithDedupTTL sets the time for which a processed message ID is remembered
+func WithDedupTTL(ttl time.Duration) KVDeduplicatorOption {
+	return func(d *KVDeduplicator) {
+		d.ttl = ttl
+	}
+}
+
+// WithDedupLease sets the time for which a message being handled is claimed.
+// It should be longer than the time taken by the handler.
+func WithDedupLease(lease time.Duration) KVDeduplicatorOption {
+	return func(d *KVDeduplicator) {
+		d.lease = lease
+	}
+}
+
+func (d *KVDeduplicator) key(topic string, id string) string {
+	return fmt.Sprintf("%s/%s/%s", d.prefix, topic, id)
+}
+
+// claimTxn puts the record of the claimed message ID, unless the key exists already
+type claimTxn struct {
+	record *kvstore.Record
+}
+
+// Handle puts the record with kvstore.IfAbsent
+func (t *claimTxn) Handle(ctx context.Context, store kvstore.KVStore) error {
+	_, err := store.Put(ctx, t.record, kvstore.IfAbsent())
+	return err
+}
+
+// Claim saves the message ID in the store as in progress for the lease, unless it exists already
+func (d *KVDeduplicator) Claim(ctx context.Context, topic string, id string) (bool, error) {
+	err := d.store.Txn(ctx, &claimTxn{&kvstore.Record{
+		Key:    d.key(topic, id),
+		Value:  []byte(DEDUP_IN_PROGRESS),
+		Expiry: d.lease,
+	}})
+	if errors.Is(err, kvstore.ErrKeyExists) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Complete saves the message ID in the store as done for the TTL
+func (d *KVDeduplicator) Complete(ctx context.Context, topic string, id string) error {
+	_, err := d.store.Put(ctx, &kvstore.Record{
+		Key:    d.key(topic, id),
+		Value:  []byte(DEDUP_DONE),
+		Expiry: d.ttl,
+	})
+
+	return err
+}
+
+// Release deletes the message ID from the store
+func (d *KVDeduplicator) Release(ctx context.Context, topic string, id string) error {
+	return d.store.Delete(ctx, d.key(topic, id))
+}
+
+type dedupHandler struct {
+	logger  logger.Logger
+	topic   string
+	d       Deduplicator
+	handler Handler
+}
+
+// NewDedupHandler returns a handler that skips messages already processed for the topic.
+// Messages without an ID are always passed to the handler.
+func NewDedupHandler(l logger.Logger, topic string, d Deduplicator, handler Handler) Handler {
+	return &dedupHandler{l, topic, d, handler}
+}
+
+// Handle passes the message to the underlying handler, unless it was already claimed.
+// The message ID is claimed before the message is handled, so that a message delivered
+// to several subscribers at once is handled only by one of them, and marked as done
+// once it's handled successfully.
+func (h *dedupHandler) Handle(ctx context.Context, m *Message) error {
+	if m.ID == "" {
+		return h.handler.Handle(ctx, m)
+	}
+
+	claimed, err := h.d.Claim(ctx, h.topic, m.ID)
+	if err != nil {
+		return fmt.Errorf("deduplication claim error: %v", err)
+	}
+
+	if !claimed {
+		return nil
+	}
+
+	if err := h.handler.Handle(ctx, m); err != nil {
+		// The handler error is returned for the message to be redelivered,
+		// even if the claim can't be released.
+		if err := h.d.Release(ctx, h.topic, m.ID); err != nil {
+			h.logger.Errorw("deduplication release error", "topic", h.topic, "id", m.ID, "error", err)
+		}
+
+		return err
+	}
+
+	// The message is handled already, it isn't failed if it can't be marked as done.
+	// It may be handled again once the claim expires.
+	if err := h.d.Complete(ctx, h.topic, m.ID); err != nil {
+		h.logger.Errorw("deduplication complete error", "topic", h.topic, "id", m.ID, "error", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,225 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/easeq/go-service/component"
+	"github.com/easeq/go-service/kvstore"
+	"github.com/stretchr/testify/require"
+)
+
+type memStore struct {
+	records map[string]*kvstore.Record
+	expires map[string]time.Time
+	sync.Mutex
+}
+
+func newMemStore() *memStore {
+	return &memStore{
+		records: make(map[string]*kvstore.Record),
+		expires: make(map[string]time.Time),
+	}
+}
+
+// record returns the record of the key, unless it expired
+func (s *memStore) record(key string) (*kvstore.Record, bool) {
+	record, ok := s.records[key]
+	if ok && record.Expiry > 0 && time.Now().After(s.expires[key]) {
+		return nil, false
+	}
+
+	return record, ok
+}
+
+func (s *memStore) Init(opts ...kvstore.Option) error { return nil }
+
+func (s *memStore) Put(ctx context.Context, record *kvstore.Record, opts ...kvstore.SetOpt) (*kvstore.Record, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if _, ok := s.record(record.Key); ok && kvstore.HasIfAbsent(opts...) {
+		return nil, kvstore.ErrKeyExists
+	}
+
+	s.records[record.Key] = record
+	s.expires[record.Key] = time.Now().Add(record.Expiry)
+	return record, nil
+}
+
+func (s *memStore) Get(ctx context.Context, key string, opts ...kvstore.GetOpt) ([]*kvstore.Record, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	record, ok := s.record(key)
+	if !ok {
+		return nil, kvstore.ErrNoResults
+	}
+
+	return []*kvstore.Record{record}, nil
+}
+
+func (s *memStore) Delete(ctx context.Context, key string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	delete(s.records, key)
+	delete(s.expires, key)
+	return nil
+}
+
+func (s *memStore) Txn(ctx context.Context, handler kvstore.TxnHandler) error {
+	return handler.Handle(ctx, s)
+}
+
+func (s *memStore) Subscribe(ctx context.Context, key string, handler kvstore.SubscribeHandler, opts ...kvstore.SubscribeOpt) error {
+	return nil
+}
+
+func (s *memStore) Unsubscribe(ctx context.Context, key string) error { return nil }
+
+func (s *memStore) String() string { return "memory" }
+
+func (s *memStore) HasInitializer() bool { return false }
+
+func (s *memStore) Initializer() component.Initializer { return nil }
+
+type countingHandler struct {
+	sync.Mutex
+	calls int
+	err   error
+}
+
+func (h *countingHandler) Handle(ctx context.Context, m *Message) error {
+	h.Lock()
+	defer h.Unlock()
+
+	h.calls++
+	return h.err
+}
+
+func TestDedupHandler(t *testing.T) {
+	tests := []struct {
+		name      string
+		ids       []string
+		handleErr error
+		wantCalls int
+	}{
+		{
+			name:      "UniqueIDs",
+			ids:       []string{"a", "b", "c"},
+			wantCalls: 3,
+		},
+		{
+			name:      "DuplicateIDs",
+			ids:       []string{"a", "a", "b", "a", "b"},
+			wantCalls: 2,
+		},
+		{
+			name:      "EmptyIDs",
+			ids:       []string{"", "", ""},
+			wantCalls: 3,
+		},
+		{
+			name:      "FailedHandlerIsRetried",
+			ids:       []string{"a", "a"},
+			handleErr: errors.New("handle error"),
+			wantCalls: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			h := &countingHandler{err: tt.handleErr}
+			d := NewKVDeduplicator(newMemStore())
+			dh := NewDedupHandler(nil, "test-topic", d, h)
+
+			for _, id := range tt.ids {
+				err := dh.Handle(context.TODO(), &Message{ID: id})
+				require.Equal(tt.handleErr, err)
+			}
+
+			require.Equal(tt.wantCalls, h.calls)
+		})
+	}
+}
+
+func TestDedupConcurrentDelivery(t *testing.T) {
+	require := require.New(t)
+
+	h := new(countingHandler)
+	dh := NewDedupHandler(nil, "test-topic", NewKVDeduplicator(newMemStore()), h)
+
+	// The same message delivered to several replicas at once is handled once
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			require.NoError(dh.Handle(context.TODO(), &Message{ID: "a"}))
+		}()
+	}
+
+	wg.Wait()
+	require.Equal(1, h.calls)
+}
+
+func TestDedupTopics(t *testing.T) {
+	require := require.New(t)
+
+	d := NewKVDeduplicator(newMemStore(), WithDedupPrefix("test"))
+	claimed, err := d.Claim(context.TODO(), "topic-a", "id")
+	require.NoError(err)
+	require.True(claimed)
+
+	claimed, err = d.Claim(context.TODO(), "topic-a", "id")
+	require.NoError(err)
+	require.False(claimed)
+
+	claimed, err = d.Claim(context.TODO(), "topic-b", "id")
+	require.NoError(err)
+	require.True(claimed)
+
+	require.NoError(d.Release(context.TODO(), "topic-a", "id"))
+	claimed, err = d.Claim(context.TODO(), "topic-a", "id")
+	require.NoError(err)
+	require.True(claimed)
+}
+
+func TestDedupLease(t *testing.T) {
+	require := require.New(t)
+
+	store := newMemStore()
+	d := NewKVDeduplicator(store, WithDedupLease(10*time.Millisecond), WithDedupTTL(time.Hour))
+
+	// The subscriber stopped while handling the message, the claim expires after the lease
+	claimed, err := d.Claim(context.TODO(), "test-topic", "a")
+	require.NoError(err)
+	require.True(claimed)
+
+	claimed, err = d.Claim(context.TODO(), "test-topic", "a")
+	require.NoError(err)
+	require.False(claimed)
+
+	time.Sleep(20 * time.Millisecond)
+
+	h := new(countingHandler)
+	dh := NewDedupHandler(nil, "test-topic", d, h)
+	require.NoError(dh.Handle(context.TODO(), &Message{ID: "a"}))
+	require.Equal(1, h.calls)
+
+	// The message handled is done for the TTL
+	records, err := store.Get(context.TODO(), d.key("test-topic", "a"))
+	require.NoError(err)
+	require.Equal(DEDUP_DONE, string(records[0].Value))
+
+	time.Sleep(20 * time.Millisecond)
+
+	require.NoError(dh.Handle(context.TODO(), &Message{ID: "a"}))
+	require.Equal(1, h.calls)
+}
@@ -2,6 +2,7 @@ package jetstream
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/Netflix/go-env"
 
@@ -12,6 +13,8 @@ import (
 type Config struct {
 	Host string `env:"NATS_HOST,default=127.0.0.1"`
 	Port string `env:"NATS_PORT,default=4222"`
+	// Duplicates is the window in which the stream discards messages with a repeated Nats-Msg-Id
+	Duplicates time.Duration `env:"NATS_STREAM_DUPLICATES,default=2m"`
 }
 
 // NewConfig returns the parsed config for jetstream from env
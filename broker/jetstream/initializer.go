@@ -54,7 +54,7 @@ func (i *Initializer) CanStop() bool {
 
 // Stop - stops the running
 func (i *Initializer) Stop(ctx context.Context) error {
-	for _, sub := range i.j.Subscriptions {
+	for _, sub := range i.j.subscriptions() {
 		if err := sub.Unsubscribe(); err != nil {
 			i.j.logger.Errorw(
 				"JetStream close connection error: %s",
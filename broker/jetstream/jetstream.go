@@ -2,10 +2,9 @@ package jetstream
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"time"
+	"sync"
 
 	"github.com/easeq/go-service/broker"
 	"github.com/easeq/go-service/component"
@@ -20,6 +19,8 @@ var (
 	ErrInvalidMessageHandler = errors.New("invalid message handler provided")
 	// ErrSubscriptionFailed returned when subscription fails
 	ErrSubscriptionFailed = errors.New("nats subscription failed")
+	// ErrNotSubscribed returned when unsubscribing from a topic without a subscription
+	ErrNotSubscribed = errors.New("not subscribed to the topic")
 )
 
 // Nsq holds our broker instance
@@ -31,6 +32,10 @@ type JetStream struct {
 	tracer        tracer.Tracer
 	jsCtx         nats.JetStreamContext
 	Subscriptions map[string]*nats.Subscription
+	// Responders holds the subscription of each topic responded to.
+	// Subscriptions and Responders are guarded by mu.
+	Responders map[string]*nats.Subscription
+	mu         sync.Mutex
 	*Config
 }
 
@@ -53,6 +58,7 @@ func NewJetStream(opts ...broker.Option) *JetStream {
 		jsCtx:         js,
 		Config:        config,
 		Subscriptions: make(map[string]*nats.Subscription),
+		Responders:    make(map[string]*nats.Subscription),
 	}
 
 	for _, opt := range opts {
@@ -99,6 +105,7 @@ func (j *JetStream) createStream(name string, subjects ...string) error {
 	if streamInfo := j.streamExists(name); streamInfo != nil {
 		newStreamCfg := streamInfo.Config
 		newStreamCfg.Retention = nats.InterestPolicy
+		newStreamCfg.Duplicates = j.Config.Duplicates
 		newStreamCfg.Subjects = utils.Unique(
 			append(newStreamCfg.Subjects, subjects...),
 		)
@@ -111,7 +118,7 @@ func (j *JetStream) createStream(name string, subjects ...string) error {
 		Name:       name,
 		Subjects:   subjects,
 		Retention:  nats.InterestPolicy,
-		Duplicates: 0 * time.Second,
+		Duplicates: j.Config.Duplicates,
 		Discard:    nats.DiscardOld,
 		// Retention: nats.InterestPolicy,
 		// Replicas:  1,
@@ -122,13 +129,14 @@ func (j *JetStream) createStream(name string, subjects ...string) error {
 
 // Publish publishes the topic message
 func (j *JetStream) Publish(ctx context.Context, topic string, message interface{}, opts ...broker.PublishOption) error {
-	payload, err := json.Marshal(message)
+	payload, err := broker.Marshal(message)
 	if err != nil {
 		return fmt.Errorf("marshalling error: %v", err)
 	}
 
 	publisher := NewPublisher(j, topic, opts...)
 	return j.w.Publish(ctx, topic, payload, func(t *broker.TraceMsgCarrier) error {
+		t.SetMessageID(publisher.id)
 		data, err := t.Bytes()
 		if err != nil {
 			j.logger.Errorw("publish[data error]", "topic", topic, "err", err)
@@ -136,12 +144,16 @@ func (j *JetStream) Publish(ctx context.Context, topic string, message interface
 		}
 
 		// Send the message with span over NATS
-		_, err = j.jsCtx.Publish(topic, data, publisher.opts...)
+		ack, err := j.jsCtx.Publish(topic, data, publisher.opts...)
 		if err != nil {
 			j.logger.Errorw("publish error", "topic", topic, "err", err)
 			return err
 		}
 
+		if ack.Duplicate {
+			j.logger.Debugw("duplicate message discarded", "topic", topic, "id", publisher.id)
+		}
+
 		return nil
 	})
 }
@@ -149,6 +161,10 @@ func (j *JetStream) Publish(ctx context.Context, topic string, message interface
 // Subscribe subcribes for the given topic.
 func (j *JetStream) Subscribe(ctx context.Context, topic string, handler broker.Handler, opts ...broker.SubscribeOption) error {
 	subscriber := NewSubscriber(j, topic, opts...)
+	if subscriber.dedup != nil {
+		handler = broker.NewDedupHandler(j.logger, topic, subscriber.dedup, handler)
+	}
+
 	natsHandler := func(m *nats.Msg) {
 		// Create new TraceMsg from normal NATS message.
 		j.w.Subscribe(ctx, m.Subject, m.Data, func(
@@ -156,6 +172,7 @@ func (j *JetStream) Subscribe(ctx context.Context, topic string, handler broker.
 			t *broker.TraceMsgCarrier,
 		) error {
 			if err := handler.Handle(ctx, &broker.Message{
+				ID:   t.MessageID(),
 				Body: t.Message,
 				Extras: map[string]interface{}{
 					broker.KEY_TRACE_MSG_CARRIER: t,
@@ -179,13 +196,155 @@ func (j *JetStream) Subscribe(ctx context.Context, topic string, handler broker.
 		return err
 	}
 
+	j.mu.Lock()
 	j.Subscriptions[topic] = subscription
+	j.mu.Unlock()
+	return nil
+}
+
+// Request publishes the message over core NATS and waits for the reply
+func (j *JetStream) Request(
+	ctx context.Context,
+	topic string,
+	message interface{},
+	opts ...broker.RequestOption,
+) (*broker.Message, error) {
+	payload, err := broker.Marshal(message)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling error: %v", err)
+	}
+
+	o := broker.NewRequestOptions(opts...)
+	ctx, cancel := context.WithTimeout(ctx, o.Timeout)
+	defer cancel()
+
+	var raw *nats.Msg
+	reply, err := j.w.Request(ctx, topic, payload, func(t *broker.TraceMsgCarrier) (*broker.TraceMsgCarrier, error) {
+		data, err := t.Bytes()
+		if err != nil {
+			return nil, fmt.Errorf("payload conversion error: %v", err)
+		}
+
+		raw, err = j.nc.RequestWithContext(ctx, topic, data)
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, nats.ErrTimeout) {
+			return nil, broker.ErrRequestTimeout
+		}
+
+		if err != nil {
+			j.logger.Errorw("request error", "topic", topic, "err", err)
+			return nil, err
+		}
+
+		return broker.NewTraceMsgCarrierFromBytes(raw.Data), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return broker.NewReplyMessage(reply, raw)
+}
+
+// Respond subscribes to the topic over core NATS and replies to the requests received.
+// Use WithQueueName to load balance the requests between the responders.
+func (j *JetStream) Respond(
+	ctx context.Context,
+	topic string,
+	handler broker.ReplyHandler,
+	opts ...broker.SubscribeOption,
+) error {
+	subscriber := NewSubscriber(j, topic, opts...)
+	natsHandler := func(m *nats.Msg) {
+		if err := j.w.Respond(ctx, m.Subject, m.Data, func(
+			ctx context.Context,
+			t *broker.TraceMsgCarrier,
+		) ([]byte, error) {
+			return broker.HandleReply(ctx, handler, &broker.Message{
+				ID:   t.MessageID(),
+				Body: t.Message,
+				Extras: map[string]interface{}{
+					broker.KEY_TRACE_MSG_CARRIER: t,
+					broker.KEY_BROKER_MSG:        m,
+				},
+			})
+		}, func(t *broker.TraceMsgCarrier) error {
+			data, err := t.Bytes()
+			if err != nil {
+				return fmt.Errorf("payload conversion error: %v", err)
+			}
+
+			return m.Respond(data)
+		}); err != nil {
+			j.logger.Errorw("respond error", "topic", topic, "err", err)
+		}
+	}
+
+	var (
+		subscription *nats.Subscription
+		err          error
+	)
+	if subscriber.queueName != "" {
+		subscription, err = j.nc.QueueSubscribe(topic, subscriber.queueName, natsHandler)
+	} else {
+		subscription, err = j.nc.Subscribe(topic, natsHandler)
+	}
+
+	if err != nil {
+		j.logger.Errorw("respond subscribe error", "topic", topic, "err", err)
+		return err
+	}
+
+	j.mu.Lock()
+	prev := j.Responders[topic]
+	j.Responders[topic] = subscription
+	j.mu.Unlock()
+
+	if prev != nil {
+		return prev.Unsubscribe()
+	}
+
 	return nil
 }
 
-// Ubsubscribe method is not applicable
+// Unsubscribe removes the subscription and the responder of the topic
 func (j *JetStream) Unsubscribe(topic string) error {
-	return j.Subscriptions[topic].Unsubscribe()
+	j.mu.Lock()
+	subscription, responder := j.Subscriptions[topic], j.Responders[topic]
+	delete(j.Subscriptions, topic)
+	delete(j.Responders, topic)
+	j.mu.Unlock()
+
+	if subscription == nil && responder == nil {
+		return ErrNotSubscribed
+	}
+
+	for _, sub := range []*nats.Subscription{subscription, responder} {
+		if sub == nil {
+			continue
+		}
+
+		if err := sub.Unsubscribe(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// subscriptions returns the subscriptions and the responders of all the topics
+func (j *JetStream) subscriptions() []*nats.Subscription {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	subs := make([]*nats.Subscription, 0, len(j.Subscriptions)+len(j.Responders))
+	for _, sub := range j.Subscriptions {
+		subs = append(subs, sub)
+	}
+
+	for _, sub := range j.Responders {
+		subs = append(subs, sub)
+	}
+
+	return subs
 }
 
 func (j *JetStream) HasInitializer() bool {
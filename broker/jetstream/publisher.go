@@ -7,6 +7,7 @@ import (
 
 // Subscriber holds additional options for jetstream subscription
 type publisher struct {
+	id   string
 	opts []nats.PubOpt
 }
 
@@ -31,3 +32,13 @@ func WithNatsPubOpts(opts ...nats.PubOpt) broker.PublishOption {
 		p.(*publisher).opts = pubOpts
 	}
 }
+
+// SetMessageID sets the Nats-Msg-Id used by JetStream to discard duplicate messages
+func (p *publisher) SetMessageID(id string) {
+	if id == "" {
+		return
+	}
+
+	p.id = id
+	p.opts = append(p.opts, nats.MsgId(id))
+}
@@ -28,6 +28,7 @@ type subscriber struct {
 	durableName string
 	queueName   string
 	sType       uint
+	dedup       broker.Deduplicator
 	opts        []nats.SubOpt
 }
 
@@ -82,6 +83,13 @@ func WithQueueName(name string) broker.SubscribeOption {
 	}
 }
 
+// SetDeduplicator sets the consumer-side deduplicator for the subscription.
+// JetStream already discards duplicates published within the stream's duplicates window,
+// this is only required to deduplicate redeliveries or retries outside of the window.
+func (s *subscriber) SetDeduplicator(d broker.Deduplicator) {
+	s.dedup = d
+}
+
 func (s *subscriber) Subscribe(handler func(m *nats.Msg)) (*nats.Subscription, error) {
 	switch s.sType {
 	case QUEUE:
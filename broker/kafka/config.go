@@ -0,0 +1,91 @@
+package kafka
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Netflix/go-env"
+	"github.com/segmentio/kafka-go"
+
+	"github.com/easeq/go-service/component"
+)
+
+// Config holds kafka configuration
+type Config struct {
+	// Brokers is a comma separated list of kafka broker addresses
+	Brokers string `env:"KAFKA_BROKERS,default=127.0.0.1:9092"`
+	// GroupID is the default consumer group of the subscriptions
+	GroupID string `env:"KAFKA_GROUP_ID,default=go-service"`
+	// StartOffset is the offset used by new consumer groups, either "first" or "last"
+	StartOffset string `env:"KAFKA_START_OFFSET,default=last"`
+	// MinBytes is the minimum batch size the consumer waits for
+	MinBytes int `env:"KAFKA_MIN_BYTES,default=1"`
+	// MaxBytes is the maximum batch size the consumer accepts
+	MaxBytes int `env:"KAFKA_MAX_BYTES,default=10485760"`
+	// MaxWait is the maximum time the consumer waits for MinBytes
+	MaxWait time.Duration `env:"KAFKA_MAX_WAIT,default=500ms"`
+	// BatchTimeout is the time the producer waits before sending an incomplete batch
+	BatchTimeout time.Duration `env:"KAFKA_BATCH_TIMEOUT,default=10ms"`
+	// RequiredAcks is the number of acks required by the producer (-1 for all replicas)
+	RequiredAcks int `env:"KAFKA_REQUIRED_ACKS,default=-1"`
+	// RetryBackoff is the time to wait before handling a failed message again
+	RetryBackoff time.Duration `env:"KAFKA_RETRY_BACKOFF,default=1s"`
+	// MaxRetries is the number of times a failed message is handled again before it's skipped,
+	// a negative value retries the message until it succeeds
+	MaxRetries int `env:"KAFKA_MAX_RETRIES,default=10"`
+}
+
+// NewConfig returns the parsed config for kafka from env
+func NewConfig() *Config {
+	c := new(Config)
+	component.NewConfig(c)
+
+	return c
+}
+
+// UnmarshalEnv env.EnvSet to Config
+func (c *Config) UnmarshalEnv(es env.EnvSet) error {
+	return env.Unmarshal(es, c)
+}
+
+// GetBrokers returns the list of kafka broker addresses
+func (c *Config) GetBrokers() []string {
+	if c.Brokers == "" {
+		return []string{}
+	}
+
+	return strings.Split(c.Brokers, ",")
+}
+
+// GetStartOffset returns the kafka start offset for new consumer groups
+func (c *Config) GetStartOffset() int64 {
+	if c.StartOffset == "first" {
+		return kafka.FirstOffset
+	}
+
+	return kafka.LastOffset
+}
+
+// Writer returns a new kafka writer.
+// Messages with the same key are published to the same partition.
+func (c *Config) Writer() *kafka.Writer {
+	return &kafka.Writer{
+		Addr:         kafka.TCP(c.GetBrokers()...),
+		Balancer:     &kafka.Hash{},
+		BatchTimeout: c.BatchTimeout,
+		RequiredAcks: kafka.RequiredAcks(c.RequiredAcks),
+	}
+}
+
+// Reader returns a new kafka reader for the topic and consumer group
+func (c *Config) Reader(topic string, groupID string) *kafka.Reader {
+	return kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     c.GetBrokers(),
+		GroupID:     groupID,
+		Topic:       topic,
+		MinBytes:    c.MinBytes,
+		MaxBytes:    c.MaxBytes,
+		MaxWait:     c.MaxWait,
+		StartOffset: c.GetStartOffset(),
+	})
+}
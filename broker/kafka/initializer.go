@@ -0,0 +1,56 @@
+package kafka
+
+import (
+	"context"
+
+	"github.com/easeq/go-service/logger"
+	"github.com/easeq/go-service/tracer"
+)
+
+type Initializer struct {
+	k *Kafka
+}
+
+// NewInitializer returns a new Kafka Initialiazer
+func NewInitializer(k *Kafka) *Initializer {
+	return &Initializer{k}
+}
+
+// AddDependency adds necessary service components as dependencies
+func (i *Initializer) AddDependency(dep interface{}) error {
+	switch v := dep.(type) {
+	case logger.Logger:
+		i.k.logger = v
+	case tracer.Tracer:
+		i.k.tracer = v
+	}
+
+	return nil
+}
+
+// Dependencies returns the string names of service components
+// that are required as dependencies for this component
+func (i *Initializer) Dependencies() []string {
+	return []string{logger.LOGGER, tracer.TRACER}
+}
+
+// CanRun returns true if the component has anything to Run
+func (i *Initializer) CanRun() bool {
+	return false
+}
+
+// Run start the service component
+func (i *Initializer) Run(ctx context.Context) error {
+	i.k.logger.Infow("Unimplemented")
+	return nil
+}
+
+// CanStop returns true if the component has anything to Stop
+func (i *Initializer) CanStop() bool {
+	return true
+}
+
+// Stop - stops the consumers and closes the producer
+func (i *Initializer) Stop(ctx context.Context) error {
+	return i.k.Close()
+}
@@ -0,0 +1,312 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/easeq/go-service/broker"
+	"github.com/easeq/go-service/component"
+	"github.com/easeq/go-service/logger"
+	"github.com/easeq/go-service/tracer"
+	"github.com/segmentio/kafka-go"
+)
+
+var (
+	// ErrNotSubscribed returned when unsubscribing from a topic without a subscription
+	ErrNotSubscribed = errors.New("not subscribed to the topic")
+)
+
+// messageWriter publishes messages to kafka
+type messageWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// messageReader consumes messages of a consumer group from kafka
+type messageReader interface {
+	FetchMessage(ctx context.Context) (kafka.Message, error)
+	CommitMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// consumer holds the reader of a subscription and the means to stop it
+type consumer struct {
+	reader messageReader
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// stop stops consuming messages and closes the reader
+func (c *consumer) stop() error {
+	c.cancel()
+	<-c.done
+
+	return c.reader.Close()
+}
+
+// Kafka holds our broker instance
+type Kafka struct {
+	i         component.Initializer
+	w         *broker.Wrapper
+	logger    logger.Logger
+	tracer    tracer.Tracer
+	writer    messageWriter
+	newReader func(topic string, groupID string) messageReader
+	// consumers holds the consumers of each subscribed topic by consumer group, guarded by mu
+	consumers map[string]map[string]*consumer
+	mu        sync.Mutex
+	*Config
+}
+
+// NewKafka returns a new instance of kafka
+func NewKafka(opts ...broker.Option) *Kafka {
+	config := NewConfig()
+
+	k := &Kafka{
+		writer: config.Writer(),
+		newReader: func(topic string, groupID string) messageReader {
+			return config.Reader(topic, groupID)
+		},
+		consumers: make(map[string]map[string]*consumer),
+		Config:    config,
+	}
+
+	for _, opt := range opts {
+		opt(k)
+	}
+
+	k.w = broker.NewWrapper(k)
+	k.i = NewInitializer(k)
+	return k
+}
+
+// Logger returns the initialized logger instance
+func (k *Kafka) Logger() logger.Logger {
+	return k.logger
+}
+
+// Publish publishes the topic message.
+// Use WithPartitionKey to publish related messages to the same partition.
+func (k *Kafka) Publish(ctx context.Context, topic string, message interface{}, opts ...broker.PublishOption) error {
+	payload, err := broker.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("marshalling error: %v", err)
+	}
+
+	publisher := NewPublisher(k, topic, opts...)
+	return k.w.Publish(ctx, topic, payload, func(t *broker.TraceMsgCarrier) error {
+		t.SetMessageID(publisher.id)
+		data, err := t.Bytes()
+		if err != nil {
+			return fmt.Errorf("payload conversion error: %v", err)
+		}
+
+		// Send the message with span over kafka
+		if err := k.writer.WriteMessages(ctx, kafka.Message{
+			Topic: t.Topic,
+			Key:   publisher.key,
+			Value: data,
+		}); err != nil {
+			k.logger.Errorw("publish error", "topic", topic, "err", err)
+			return err
+		}
+
+		return nil
+	})
+}
+
+// Subscribe subcribes for the given topic using the subscription's consumer group.
+// The offset of a message is committed only after it's handled successfully.
+// The previous subscription of the topic with the same consumer group is stopped.
+func (k *Kafka) Subscribe(ctx context.Context, topic string, handler broker.Handler, opts ...broker.SubscribeOption) error {
+	subscriber := NewSubscriber(k, topic, opts...)
+	if subscriber.dedup != nil {
+		handler = broker.NewDedupHandler(k.logger, topic, subscriber.dedup, handler)
+	}
+
+	cctx, cancel := context.WithCancel(ctx)
+	c := &consumer{
+		reader: k.newReader(topic, subscriber.groupID),
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	k.mu.Lock()
+	groups, ok := k.consumers[topic]
+	if !ok {
+		groups = make(map[string]*consumer)
+		k.consumers[topic] = groups
+	}
+
+	prev := groups[subscriber.groupID]
+	groups[subscriber.groupID] = c
+	k.mu.Unlock()
+
+	if prev != nil {
+		if err := prev.stop(); err != nil {
+			k.logger.Errorw("previous consumer close error", "topic", topic, "group", subscriber.groupID, "err", err)
+		}
+	}
+
+	go k.consume(cctx, c, topic, subscriber, handler)
+	return nil
+}
+
+// consume fetches the messages and commits their offsets once handled
+func (k *Kafka) consume(
+	ctx context.Context,
+	c *consumer,
+	topic string,
+	s *subscriber,
+	handler broker.Handler,
+) {
+	defer close(c.done)
+
+	for {
+		m, err := c.reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			k.logger.Errorw("fetch error", "topic", topic, "err", err)
+			if !k.wait(ctx, k.RetryBackoff) {
+				return
+			}
+
+			continue
+		}
+
+		if !k.handle(ctx, m, s, handler) {
+			// Stopped before the message was handled,
+			// the offset is not committed and the message will be redelivered.
+			return
+		}
+
+		if err := c.reader.CommitMessages(ctx, m); err != nil {
+			k.logger.Errorw("commit error", "topic", topic, "offset", m.Offset, "err", err)
+		}
+	}
+}
+
+// carrier returns the trace message carrier of the message published by go-service.
+// The value of the messages published by other producers is passed as is,
+// along with their kafka headers holding the trace context and the message ID, if any.
+func carrier(m kafka.Message) *broker.TraceMsgCarrier {
+	if t := broker.NewTraceMsgCarrierFromBytes(m.Value); t != nil {
+		return t
+	}
+
+	t := broker.NewTraceMsgCarrier(m.Topic, m.Value)
+	for _, h := range m.Headers {
+		t.Set(h.Key, string(h.Value))
+	}
+
+	return t
+}
+
+// handle passes the message to the handler until it succeeds or the retries are exhausted.
+// It returns false if the context is done before that.
+func (k *Kafka) handle(ctx context.Context, m kafka.Message, s *subscriber, handler broker.Handler) bool {
+	for attempt := 1; ; attempt++ {
+		err := k.w.SubscribeCarrier(ctx, carrier(m), func(ctx context.Context, t *broker.TraceMsgCarrier) error {
+			return handler.Handle(ctx, &broker.Message{
+				ID:   t.MessageID(),
+				Body: t.Message,
+				Extras: map[string]interface{}{
+					broker.KEY_TRACE_MSG_CARRIER: t,
+					broker.KEY_BROKER_MSG:        m,
+				},
+			})
+		})
+		if err == nil {
+			return true
+		}
+
+		k.logger.Errorw(
+			"subscribe handle error",
+			"topic", m.Topic,
+			"partition", m.Partition,
+			"offset", m.Offset,
+			"attempt", attempt,
+			"err", err,
+		)
+
+		if s.maxRetries >= 0 && attempt > s.maxRetries {
+			k.logger.Errorw("skipping message after max retries", "topic", m.Topic, "offset", m.Offset)
+			return true
+		}
+
+		if !k.wait(ctx, k.RetryBackoff) {
+			return false
+		}
+	}
+}
+
+// wait waits for the given duration, it returns false if the context is done before
+func (k *Kafka) wait(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// Unsubscribe stops consuming the topic and leaves the consumer groups of its subscriptions
+func (k *Kafka) Unsubscribe(topic string) error {
+	k.mu.Lock()
+	groups := k.consumers[topic]
+	delete(k.consumers, topic)
+	k.mu.Unlock()
+
+	if len(groups) == 0 {
+		return ErrNotSubscribed
+	}
+
+	var err error
+	for group, c := range groups {
+		if cerr := c.stop(); cerr != nil {
+			k.logger.Errorw("consumer close error", "topic", topic, "group", group, "err", cerr)
+			err = cerr
+		}
+	}
+
+	return err
+}
+
+// Close stops all the consumers and closes the producer
+func (k *Kafka) Close() error {
+	k.mu.Lock()
+	consumers := k.consumers
+	k.consumers = make(map[string]map[string]*consumer)
+	k.mu.Unlock()
+
+	for topic, groups := range consumers {
+		for group, c := range groups {
+			if err := c.stop(); err != nil {
+				k.logger.Errorw("consumer close error", "topic", topic, "group", group, "err", err)
+			}
+		}
+	}
+
+	return k.writer.Close()
+}
+
+func (k *Kafka) HasInitializer() bool {
+	return true
+}
+
+func (k *Kafka) Initializer() component.Initializer {
+	return k.i
+}
+
+func (k *Kafka) String() string {
+	return "kafka"
+}
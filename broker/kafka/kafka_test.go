@@ -0,0 +1,280 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/easeq/go-service/broker"
+	"github.com/easeq/go-service/logger"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/require"
+)
+
+type nopLogger struct {
+	logger.Logger
+}
+
+func (nopLogger) Debugw(message string, args ...interface{}) {}
+
+func (nopLogger) Infow(message string, args ...interface{}) {}
+
+func (nopLogger) Errorw(message string, args ...interface{}) {}
+
+// memQueue is an in-memory kafka topic shared by the fake writer and readers
+type memQueue struct {
+	msgs      chan kafka.Message
+	committed []kafka.Message
+	closed    bool
+	sync.Mutex
+}
+
+func newMemQueue() *memQueue {
+	return &memQueue{msgs: make(chan kafka.Message, 10)}
+}
+
+func (q *memQueue) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	for i, m := range msgs {
+		m.Offset = int64(len(q.msgs) + i)
+		q.msgs <- m
+	}
+
+	return nil
+}
+
+func (q *memQueue) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	select {
+	case <-ctx.Done():
+		return kafka.Message{}, ctx.Err()
+	case m := <-q.msgs:
+		return m, nil
+	}
+}
+
+func (q *memQueue) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+	q.Lock()
+	defer q.Unlock()
+
+	q.committed = append(q.committed, msgs...)
+	return nil
+}
+
+func (q *memQueue) Close() error {
+	q.Lock()
+	defer q.Unlock()
+
+	q.closed = true
+	return nil
+}
+
+func (q *memQueue) commits() []kafka.Message {
+	q.Lock()
+	defer q.Unlock()
+
+	return append([]kafka.Message{}, q.committed...)
+}
+
+type failingHandler struct {
+	failures int
+	calls    int
+	handled  chan *broker.Message
+}
+
+func (h *failingHandler) Handle(ctx context.Context, m *broker.Message) error {
+	h.calls++
+	if h.calls <= h.failures {
+		return errors.New("handle error")
+	}
+
+	h.handled <- m
+	return nil
+}
+
+func newTestKafka(q *memQueue) *Kafka {
+	k := &Kafka{
+		logger: nopLogger{},
+		writer: q,
+		newReader: func(topic string, groupID string) messageReader {
+			return q
+		},
+		consumers: make(map[string]map[string]*consumer),
+		Config: &Config{
+			GroupID:      "test",
+			RetryBackoff: time.Millisecond,
+			MaxRetries:   3,
+		},
+	}
+
+	k.w = broker.NewWrapper(k)
+	return k
+}
+
+func TestPublishPartitionKey(t *testing.T) {
+	q := newMemQueue()
+	k := newTestKafka(q)
+
+	err := k.Publish(context.Background(), "orders", "created", WithPartitionKey("order-1"), broker.WithMessageID("id-1"))
+	require.NoError(t, err)
+
+	m := <-q.msgs
+	require.Equal(t, "orders", m.Topic)
+	require.Equal(t, []byte("order-1"), m.Key)
+
+	carrier := broker.NewTraceMsgCarrierFromBytes(m.Value)
+	require.NotNil(t, carrier)
+	require.Equal(t, "id-1", carrier.MessageID())
+}
+
+func TestSubscribeCommitsAfterSuccess(t *testing.T) {
+	tests := []struct {
+		name       string
+		failures   int
+		maxRetries int
+		wantCalls  int
+		wantHandle bool
+	}{
+		{
+			name:       "handled on first attempt",
+			failures:   0,
+			wantCalls:  1,
+			wantHandle: true,
+		},
+		{
+			name:       "retried until handled",
+			failures:   2,
+			maxRetries: -1,
+			wantCalls:  3,
+			wantHandle: true,
+		},
+		{
+			name:       "skipped after max retries",
+			failures:   5,
+			maxRetries: 1,
+			wantCalls:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := newMemQueue()
+			k := newTestKafka(q)
+			h := &failingHandler{failures: tt.failures, handled: make(chan *broker.Message, 1)}
+
+			require.NoError(t, k.Publish(context.Background(), "orders", "created", broker.WithMessageID("id-1")))
+			require.NoError(t, k.Subscribe(context.Background(), "orders", h, WithMaxRetries(tt.maxRetries)))
+
+			if tt.wantHandle {
+				m := <-h.handled
+				require.Equal(t, "id-1", m.ID)
+			}
+
+			require.Eventually(t, func() bool {
+				return len(q.commits()) == 1
+			}, time.Second, time.Millisecond)
+
+			require.NoError(t, k.Unsubscribe("orders"))
+			require.Equal(t, tt.wantCalls, h.calls)
+			require.True(t, q.closed)
+		})
+	}
+}
+
+func TestUnsubscribeWithoutCommit(t *testing.T) {
+	q := newMemQueue()
+	k := newTestKafka(q)
+	h := &failingHandler{failures: 1 << 30, handled: make(chan *broker.Message, 1)}
+
+	require.NoError(t, k.Publish(context.Background(), "orders", "created"))
+	require.NoError(t, k.Subscribe(context.Background(), "orders", h, WithMaxRetries(-1)))
+
+	require.Eventually(t, func() bool {
+		q.Lock()
+		defer q.Unlock()
+		return len(q.msgs) == 0
+	}, time.Second, time.Millisecond)
+
+	require.NoError(t, k.Unsubscribe("orders"))
+	require.Empty(t, q.commits())
+	require.ErrorIs(t, k.Unsubscribe("orders"), ErrNotSubscribed)
+}
+
+func TestSubscribeDefaultMaxRetries(t *testing.T) {
+	q := newMemQueue()
+	k := newTestKafka(q)
+	h := &failingHandler{failures: 1 << 30, handled: make(chan *broker.Message, 1)}
+
+	// The failed message is skipped after KAFKA_MAX_RETRIES instead of blocking the partition
+	require.NoError(t, k.Publish(context.Background(), "orders", "created"))
+	require.NoError(t, k.Subscribe(context.Background(), "orders", h))
+
+	require.Eventually(t, func() bool {
+		return len(q.commits()) == 1
+	}, time.Second, time.Millisecond)
+
+	require.NoError(t, k.Unsubscribe("orders"))
+	require.Equal(t, k.MaxRetries+1, h.calls)
+}
+
+func TestSubscribePlainRecord(t *testing.T) {
+	q := newMemQueue()
+	k := newTestKafka(q)
+	h := &failingHandler{handled: make(chan *broker.Message, 1)}
+
+	// A record published by a producer other than go-service
+	q.msgs <- kafka.Message{
+		Topic: "orders",
+		Value: []byte(`{"id":"order-1"}`),
+		Headers: []kafka.Header{
+			{Key: broker.HEADER_MESSAGE_ID, Value: []byte("id-1")},
+			{Key: "traceparent", Value: []byte("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")},
+		},
+	}
+
+	require.NoError(t, k.Subscribe(context.Background(), "orders", h))
+
+	m := <-h.handled
+	require.Equal(t, []byte(`{"id":"order-1"}`), m.Body)
+	require.Equal(t, "id-1", m.ID)
+
+	carrier := m.Extras[broker.KEY_TRACE_MSG_CARRIER].(*broker.TraceMsgCarrier)
+	require.Contains(t, carrier.Get("traceparent"), "4bf92f3577b34da6a3ce929d0e0e4736")
+
+	var order struct {
+		ID string `json:"id"`
+	}
+	require.NoError(t, json.Unmarshal(m.Body, &order))
+	require.Equal(t, "order-1", order.ID)
+
+	require.Eventually(t, func() bool {
+		return len(q.commits()) == 1
+	}, time.Second, time.Millisecond)
+	require.NoError(t, k.Unsubscribe("orders"))
+}
+
+func TestSubscribeConsumerGroups(t *testing.T) {
+	queues := map[string]*memQueue{"billing": newMemQueue(), "shipping": newMemQueue()}
+	k := newTestKafka(newMemQueue())
+	k.newReader = func(topic string, groupID string) messageReader {
+		return queues[groupID]
+	}
+
+	// The subscriptions of the topic with other consumer groups are kept
+	for group := range queues {
+		h := &failingHandler{handled: make(chan *broker.Message, 1)}
+		require.NoError(t, k.Subscribe(context.Background(), "orders", h, WithGroupID(group)))
+	}
+
+	require.Len(t, k.consumers["orders"], 2)
+	for _, q := range queues {
+		require.False(t, q.closed)
+	}
+
+	require.NoError(t, k.Unsubscribe("orders"))
+	require.Empty(t, k.consumers)
+	for _, q := range queues {
+		require.True(t, q.closed)
+	}
+}
@@ -0,0 +1,35 @@
+package kafka
+
+import (
+	"github.com/easeq/go-service/broker"
+)
+
+// publisher holds additional options for kafka publishing
+type publisher struct {
+	id  string
+	key []byte
+}
+
+// NewPublisher returns a new publisher instance for kafka publishing
+func NewPublisher(k *Kafka, topic string, opts ...broker.PublishOption) *publisher {
+	p := &publisher{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// WithPartitionKey sets the message key used to select the partition.
+// Messages with the same key are published to the same partition, in order.
+func WithPartitionKey(key string) broker.PublishOption {
+	return func(p broker.Publisher) {
+		p.(*publisher).key = []byte(key)
+	}
+}
+
+// SetMessageID sets the idempotency key sent along with the message
+func (p *publisher) SetMessageID(id string) {
+	p.id = id
+}
@@ -0,0 +1,47 @@
+package kafka
+
+import (
+	"github.com/easeq/go-service/broker"
+)
+
+// subscriber holds additional options for kafka subscription
+type subscriber struct {
+	groupID    string
+	maxRetries int
+	dedup      broker.Deduplicator
+}
+
+// NewSubscriber returns a new subscriber instance for kafka subscription
+func NewSubscriber(k *Kafka, topic string, opts ...broker.SubscribeOption) *subscriber {
+	s := &subscriber{
+		groupID:    k.GroupID,
+		maxRetries: k.MaxRetries,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// WithGroupID sets the consumer group of the subscription
+func WithGroupID(groupID string) broker.SubscribeOption {
+	return func(s broker.Subscriber) {
+		s.(*subscriber).groupID = groupID
+	}
+}
+
+// WithMaxRetries sets the number of times a failed message is handled again
+// before its offset is committed and the message is skipped, KAFKA_MAX_RETRIES by default.
+// A negative value retries the message until it succeeds, blocking the partition meanwhile.
+func WithMaxRetries(n int) broker.SubscribeOption {
+	return func(s broker.Subscriber) {
+		s.(*subscriber).maxRetries = n
+	}
+}
+
+// SetDeduplicator sets the consumer-side deduplicator for the subscription
+func (s *subscriber) SetDeduplicator(d broker.Deduplicator) {
+	s.dedup = d
+}
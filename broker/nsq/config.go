@@ -2,6 +2,8 @@ package nsq
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/Netflix/go-env"
 	"github.com/nsqio/go-nsq"
@@ -32,6 +34,9 @@ func (p *Producer) Address() string {
 type Lookupd struct {
 	Host string `env:"BROKER_NSQ_LOOKUPD_HOST,default=127.0.0.1"`
 	Port string `env:"BROKER_NSQ_LOOKUPD_PORT,default=4161"`
+	// Hosts is a comma separated list of nsqlookupd addresses (host:port).
+	// When set, it takes precedence over Host and Port.
+	Hosts string `env:"BROKER_NSQ_LOOKUPD_HOSTS"`
 }
 
 // Address returns the formatted address for the nsq lookupd
@@ -39,10 +44,41 @@ func (l *Lookupd) Address() string {
 	return fmt.Sprintf("%s:%s", l.Host, l.Port)
 }
 
+// Addresses returns the addresses of all the nsq lookupd instances
+func (l *Lookupd) Addresses() []string {
+	if l.Hosts == "" {
+		return []string{l.Address()}
+	}
+
+	addresses := []string{}
+	for _, address := range strings.Split(l.Hosts, ",") {
+		if address = strings.TrimSpace(address); address != "" {
+			addresses = append(addresses, address)
+		}
+	}
+
+	return addresses
+}
+
+// Consumer holds config for NSQ consumers
+type Consumer struct {
+	MaxInFlight         int           `env:"BROKER_NSQ_MAX_IN_FLIGHT,default=1"`
+	MaxAttempts         uint16        `env:"BROKER_NSQ_MAX_ATTEMPTS,default=5"`
+	DialTimeout         time.Duration `env:"BROKER_NSQ_DIAL_TIMEOUT,default=1s"`
+	ReadTimeout         time.Duration `env:"BROKER_NSQ_READ_TIMEOUT,default=60s"`
+	WriteTimeout        time.Duration `env:"BROKER_NSQ_WRITE_TIMEOUT,default=1s"`
+	LookupdPollInterval time.Duration `env:"BROKER_NSQ_LOOKUPD_POLL_INTERVAL,default=60s"`
+	DefaultRequeueDelay time.Duration `env:"BROKER_NSQ_DEFAULT_REQUEUE_DELAY,default=90s"`
+	MaxRequeueDelay     time.Duration `env:"BROKER_NSQ_MAX_REQUEUE_DELAY,default=15m"`
+	BackoffMultiplier   time.Duration `env:"BROKER_NSQ_BACKOFF_MULTIPLIER,default=1s"`
+	MaxBackoffDuration  time.Duration `env:"BROKER_NSQ_MAX_BACKOFF_DURATION,default=2m"`
+}
+
 // Config holds database configuration
 type Config struct {
 	Producer Producer
 	Lookupd  Lookupd
+	Consumer Consumer
 }
 
 // UnmarshalEnv env.EnvSet to Config
@@ -57,5 +93,17 @@ func (c *Config) Channel(topic string) string {
 
 // NSQConfig returns the new config
 func (c *Config) NSQConfig() *nsq.Config {
-	return nsq.NewConfig()
+	config := nsq.NewConfig()
+	config.MaxInFlight = c.Consumer.MaxInFlight
+	config.MaxAttempts = c.Consumer.MaxAttempts
+	config.DialTimeout = c.Consumer.DialTimeout
+	config.ReadTimeout = c.Consumer.ReadTimeout
+	config.WriteTimeout = c.Consumer.WriteTimeout
+	config.LookupdPollInterval = c.Consumer.LookupdPollInterval
+	config.DefaultRequeueDelay = c.Consumer.DefaultRequeueDelay
+	config.MaxRequeueDelay = c.Consumer.MaxRequeueDelay
+	config.BackoffMultiplier = c.Consumer.BackoffMultiplier
+	config.MaxBackoffDuration = c.Consumer.MaxBackoffDuration
+
+	return config
 }
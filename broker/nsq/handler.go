@@ -27,6 +27,7 @@ func (h *nsqHandler) HandleMessage(message *nsq.Message) error {
 		message.Body,
 		func(ctx context.Context, t *broker.TraceMsgCarrier) error {
 			if err := h.handler.Handle(ctx, &broker.Message{
+				ID:   t.MessageID(),
 				Body: t.Message,
 				Extras: map[string]interface{}{
 					broker.KEY_TRACE_MSG_CARRIER: t,
@@ -50,13 +50,7 @@ func (i *Initializer) CanStop() bool {
 	return true
 }
 
-// Stop - stops the running
+// Stop - stops the consumers after draining them and stops the producer
 func (i *Initializer) Stop(ctx context.Context) error {
-	i.n.Producer.Stop()
-
-	for _, consumer := range i.n.Consumers {
-		consumer.Stop()
-	}
-
-	return nil
+	return i.n.Close(ctx)
 }
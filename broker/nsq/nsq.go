@@ -2,31 +2,37 @@ package nsq
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/easeq/go-service/broker"
 	"github.com/easeq/go-service/component"
 	"github.com/easeq/go-service/logger"
 	"github.com/easeq/go-service/tracer"
+	"github.com/easeq/go-service/utils"
 	"github.com/nsqio/go-nsq"
 )
 
 var (
 	// ErrInvalidMessageHandler returned when the message handler doesn't implement the underlying interface
 	ErrInvalidMessageHandler = errors.New("invalid message handler provided")
+	// ErrNotSubscribed returned when unsubscribing from a topic without a subscription
+	ErrNotSubscribed = errors.New("not subscribed to the topic")
 )
 
 // Nsq holds our broker instance
 type Nsq struct {
-	i         component.Initializer
-	w         *broker.Wrapper
-	t         *broker.Trace
-	logger    logger.Logger
-	tracer    tracer.Tracer
-	Producer  *nsq.Producer
-	Consumers map[string]*nsq.Consumer
+	i        component.Initializer
+	w        *broker.Wrapper
+	t        *broker.Trace
+	logger   logger.Logger
+	tracer   tracer.Tracer
+	Producer *nsq.Producer
+	// Consumers holds the consumers of each subscribed topic by channel, guarded by mu
+	Consumers map[string]map[string]*nsq.Consumer
+	mu        sync.Mutex
+	replies   *replies
 	*Config
 }
 
@@ -41,10 +47,12 @@ func NewNsq() *Nsq {
 
 	n := &Nsq{
 		Producer:  producer,
-		Consumers: make(map[string]*nsq.Consumer),
+		Consumers: make(map[string]map[string]*nsq.Consumer),
 		Config:    config,
 	}
 
+	n.replies = newReplies(n)
+
 	n.w = broker.NewWrapper(n)
 	n.i = NewInitializer(n)
 	return n
@@ -57,12 +65,14 @@ func (n *Nsq) Logger() logger.Logger {
 
 // Publish publishes the topic message
 func (n *Nsq) Publish(ctx context.Context, topic string, message interface{}, opts ...broker.PublishOption) error {
-	payload, err := json.Marshal(message)
+	payload, err := broker.Marshal(message)
 	if err != nil {
 		return fmt.Errorf("marshalling error: %v", err)
 	}
 
+	publisher := NewNsqPublisher(n, topic, opts...)
 	return n.w.Publish(ctx, topic, payload, func(t *broker.TraceMsgCarrier) error {
+		t.SetMessageID(publisher.id)
 		data, err := t.Bytes()
 		if err != nil {
 			return fmt.Errorf("payload conversion error: %v", err)
@@ -80,31 +90,227 @@ func (n *Nsq) Publish(ctx context.Context, topic string, message interface{}, op
 // Subscribe subcribes for the given topic
 func (n *Nsq) Subscribe(ctx context.Context, topic string, handler broker.Handler, opts ...broker.SubscribeOption) error {
 	subscriber := NewNsqSubscriber(n, topic, opts...)
-	consumer, err := nsq.NewConsumer(topic, subscriber.channel, n.NSQConfig())
+	if subscriber.dedup != nil {
+		handler = broker.NewDedupHandler(n.logger, topic, subscriber.dedup, handler)
+	}
+
+	return n.consume(ctx, topic, subscriber.channel, NewNsqHandler(ctx, n, topic, handler))
+}
+
+// Request publishes the message with a reply topic and waits for the reply.
+// NSQ has no native request/reply, the replies are published by the responder
+// to an ephemeral topic consumed by this broker instance.
+func (n *Nsq) Request(
+	ctx context.Context,
+	topic string,
+	message interface{},
+	opts ...broker.RequestOption,
+) (*broker.Message, error) {
+	payload, err := broker.Marshal(message)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling error: %v", err)
+	}
+
+	replyTopic, err := n.replies.topic()
+	if err != nil {
+		return nil, err
+	}
+
+	o := broker.NewRequestOptions(opts...)
+	ctx, cancel := context.WithTimeout(ctx, o.Timeout)
+	defer cancel()
+
+	id := utils.NewID()
+	cReply := n.replies.add(id)
+	defer n.replies.remove(id)
+
+	reply, err := n.w.Request(ctx, topic, payload, func(t *broker.TraceMsgCarrier) (*broker.TraceMsgCarrier, error) {
+		t.Set(broker.HEADER_REPLY_TO, replyTopic)
+		t.Set(broker.HEADER_CORRELATION_ID, id)
+		data, err := t.Bytes()
+		if err != nil {
+			return nil, fmt.Errorf("payload conversion error: %v", err)
+		}
+
+		if err := n.Producer.Publish(t.Topic, data); err != nil {
+			return nil, fmt.Errorf("request publish error: %v", err)
+		}
+
+		select {
+		case reply := <-cReply:
+			return reply, nil
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, broker.ErrRequestTimeout
+			}
+
+			return nil, ctx.Err()
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return broker.NewReplyMessage(reply, nil)
+}
+
+// Respond subscribes to the topic and publishes the replies to the topic requested
+func (n *Nsq) Respond(
+	ctx context.Context,
+	topic string,
+	handler broker.ReplyHandler,
+	opts ...broker.SubscribeOption,
+) error {
+	subscriber := NewNsqSubscriber(n, topic, opts...)
+	return n.consume(ctx, topic, subscriber.channel, nsq.HandlerFunc(func(message *nsq.Message) error {
+		return n.w.Respond(ctx, topic, message.Body, func(
+			ctx context.Context,
+			t *broker.TraceMsgCarrier,
+		) ([]byte, error) {
+			return broker.HandleReply(ctx, handler, &broker.Message{
+				ID:   t.MessageID(),
+				Body: t.Message,
+				Extras: map[string]interface{}{
+					broker.KEY_TRACE_MSG_CARRIER: t,
+					broker.KEY_BROKER_MSG:        message,
+				},
+			})
+		}, func(t *broker.TraceMsgCarrier) error {
+			if t.Topic == "" {
+				return errors.New("reply topic not provided by the requester")
+			}
+
+			data, err := t.Bytes()
+			if err != nil {
+				return fmt.Errorf("payload conversion error: %v", err)
+			}
+
+			return n.Producer.Publish(t.Topic, data)
+		})
+	}))
+}
+
+// consume creates a new consumer for the topic channel and connects it to NSQ.
+// The previous consumer of the topic channel is stopped once the new one is connected,
+// and the consumer is stopped when the context is done.
+func (n *Nsq) consume(ctx context.Context, topic string, channel string, handler nsq.Handler) error {
+	consumer, err := nsq.NewConsumer(topic, channel, n.NSQConfig())
 	if err != nil {
 		return fmt.Errorf("new consumer error: %v", err)
 	}
 
-	nsqHandler := NewNsqHandler(ctx, n, topic, handler)
-	consumer.AddHandler(nsqHandler)
+	consumer.AddHandler(handler)
 	if err := consumer.ConnectToNSQD(n.Config.Producer.Address()); err != nil {
+		stopConsumer(consumer)
 		return fmt.Errorf("consumer NSQD connection error: %v", err)
 	}
 
-	if err := consumer.ConnectToNSQLookupd(n.Config.Lookupd.Address()); err != nil {
+	if err := consumer.ConnectToNSQLookupds(n.Config.Lookupd.Addresses()); err != nil {
+		stopConsumer(consumer)
 		return fmt.Errorf("consumer NSQLookupd connection error: %v", err)
 	}
 
-	n.Consumers[topic] = consumer
+	n.mu.Lock()
+	channels, ok := n.Consumers[topic]
+	if !ok {
+		channels = make(map[string]*nsq.Consumer)
+		n.Consumers[topic] = channels
+	}
+
+	prev := channels[channel]
+	channels[channel] = consumer
+	n.mu.Unlock()
+
+	if prev != nil {
+		stopConsumer(prev)
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			n.remove(topic, channel, consumer)
+			stopConsumer(consumer)
+		case <-consumer.StopChan:
+		}
+	}()
 
 	return nil
 }
 
-// Ubsubscribe method is not applicable
+// remove removes the consumer of the topic channel, unless it has been replaced already
+func (n *Nsq) remove(topic string, channel string, consumer *nsq.Consumer) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	channels := n.Consumers[topic]
+	if channels[channel] != consumer {
+		return
+	}
+
+	delete(channels, channel)
+	if len(channels) == 0 {
+		delete(n.Consumers, topic)
+	}
+}
+
+// stopConsumer stops the consumer and waits for the in-flight messages to drain
+func stopConsumer(consumer *nsq.Consumer) {
+	consumer.Stop()
+	<-consumer.StopChan
+}
+
+// Unsubscribe stops the consumers of every channel of the topic after draining the in-flight messages
 func (n *Nsq) Unsubscribe(topic string) error {
+	n.mu.Lock()
+	channels := n.Consumers[topic]
+	delete(n.Consumers, topic)
+	n.mu.Unlock()
+
+	if len(channels) == 0 {
+		return ErrNotSubscribed
+	}
+
+	for _, consumer := range channels {
+		consumer.Stop()
+	}
+
+	for _, consumer := range channels {
+		<-consumer.StopChan
+	}
+
 	return nil
 }
 
+// Close stops all the consumers and the producer.
+// It waits for the consumers to drain until the context is done.
+func (n *Nsq) Close(ctx context.Context) error {
+	n.mu.Lock()
+	consumers := n.Consumers
+	n.Consumers = make(map[string]map[string]*nsq.Consumer)
+	n.mu.Unlock()
+
+	for _, channels := range consumers {
+		for _, consumer := range channels {
+			consumer.Stop()
+		}
+	}
+
+	var err error
+	for topic, channels := range consumers {
+		for channel, consumer := range channels {
+			select {
+			case <-consumer.StopChan:
+			case <-ctx.Done():
+				n.logger.Errorw("consumer drain error", "topic", topic, "channel", channel, "err", ctx.Err())
+				err = ctx.Err()
+			}
+		}
+	}
+
+	n.Producer.Stop()
+	return err
+}
+
 func (n *Nsq) HasInitializer() bool {
 	return true
 }
@@ -3,6 +3,9 @@ package nsq
 import (
 	"context"
 	"testing"
+
+	"github.com/nsqio/go-nsq"
+	"github.com/stretchr/testify/require"
 )
 
 func TestPublish(t *testing.T) {
@@ -35,3 +38,80 @@ func TestPublish(t *testing.T) {
 		})
 	}
 }
+
+func TestLookupdAddresses(t *testing.T) {
+	tests := []struct {
+		name    string
+		lookupd Lookupd
+		want    []string
+	}{
+		{
+			name:    "hostAndPort",
+			lookupd: Lookupd{Host: "127.0.0.1", Port: "4161"},
+			want:    []string{"127.0.0.1:4161"},
+		},
+		{
+			name:    "multipleHosts",
+			lookupd: Lookupd{Host: "127.0.0.1", Port: "4161", Hosts: "lookupd-1:4161, lookupd-2:4161,"},
+			want:    []string{"lookupd-1:4161", "lookupd-2:4161"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.lookupd.Addresses())
+		})
+	}
+}
+
+func newTestConsumer(t *testing.T, topic string, channel string) *nsq.Consumer {
+	consumer, err := nsq.NewConsumer(topic, channel, nsq.NewConfig())
+	require.NoError(t, err)
+	consumer.AddHandler(nsq.HandlerFunc(func(message *nsq.Message) error {
+		return nil
+	}))
+
+	return consumer
+}
+
+func TestUnsubscribe(t *testing.T) {
+	first := newTestConsumer(t, "test-topic", "first-channel")
+	second := newTestConsumer(t, "test-topic", "second-channel")
+
+	n := &Nsq{Consumers: map[string]map[string]*nsq.Consumer{
+		"test-topic": {"first-channel": first, "second-channel": second},
+	}}
+	require.NoError(t, n.Unsubscribe("test-topic"))
+	require.Empty(t, n.Consumers)
+
+	for _, consumer := range []*nsq.Consumer{first, second} {
+		select {
+		case <-consumer.StopChan:
+		default:
+			t.Fatal("consumer not stopped")
+		}
+	}
+
+	require.ErrorIs(t, n.Unsubscribe("test-topic"), ErrNotSubscribed)
+}
+
+func TestRemove(t *testing.T) {
+	first := newTestConsumer(t, "test-topic", "first-channel")
+	second := newTestConsumer(t, "test-topic", "second-channel")
+	replaced := newTestConsumer(t, "test-topic", "second-channel")
+
+	n := &Nsq{Consumers: map[string]map[string]*nsq.Consumer{
+		"test-topic": {"first-channel": first, "second-channel": second},
+	}}
+
+	// The consumers of the other channels of the topic are kept
+	n.remove("test-topic", "first-channel", first)
+	require.Equal(t, map[string]*nsq.Consumer{"second-channel": second}, n.Consumers["test-topic"])
+
+	// A consumer already replaced on the channel isn't removed
+	n.remove("test-topic", "second-channel", replaced)
+	require.Equal(t, map[string]*nsq.Consumer{"second-channel": second}, n.Consumers["test-topic"])
+
+	n.remove("test-topic", "second-channel", second)
+	require.Empty(t, n.Consumers)
+}
@@ -0,0 +1,26 @@
+package nsq
+
+import (
+	"github.com/easeq/go-service/broker"
+)
+
+// publisher holds additional options for nsq publishing
+type publisher struct {
+	id string
+}
+
+// NewNsqPublisher returns a new publisher instance for NSQ publishing
+func NewNsqPublisher(n *Nsq, topic string, opts ...broker.PublishOption) *publisher {
+	p := &publisher{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// SetMessageID sets the idempotency key sent along with the message
+func (p *publisher) SetMessageID(id string) {
+	p.id = id
+}
@@ -0,0 +1,88 @@
+package nsq
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/easeq/go-service/broker"
+	"github.com/easeq/go-service/utils"
+	"github.com/nsqio/go-nsq"
+)
+
+const (
+	// REPLY_CHANNEL is the channel used to consume the reply topic
+	REPLY_CHANNEL = "replies#ephemeral"
+)
+
+// replies keeps track of the requests waiting for a reply
+type replies struct {
+	n       *Nsq
+	once    sync.Once
+	name    string
+	err     error
+	pending map[string]chan *broker.TraceMsgCarrier
+	sync.Mutex
+}
+
+func newReplies(n *Nsq) *replies {
+	return &replies{
+		n:       n,
+		name:    fmt.Sprintf("reply_%s#ephemeral", utils.NewID()),
+		pending: make(map[string]chan *broker.TraceMsgCarrier),
+	}
+}
+
+// topic returns the reply topic of this broker instance.
+// The reply topic is consumed on the first request.
+func (r *replies) topic() (string, error) {
+	r.once.Do(func() {
+		r.err = r.n.consume(context.Background(), r.name, REPLY_CHANNEL, nsq.HandlerFunc(r.handle))
+	})
+
+	return r.name, r.err
+}
+
+// add registers a request waiting for the reply with the given correlation ID
+func (r *replies) add(id string) <-chan *broker.TraceMsgCarrier {
+	r.Lock()
+	defer r.Unlock()
+
+	cReply := make(chan *broker.TraceMsgCarrier, 1)
+	r.pending[id] = cReply
+	return cReply
+}
+
+// remove unregisters the request with the given correlation ID
+func (r *replies) remove(id string) {
+	r.Lock()
+	defer r.Unlock()
+
+	delete(r.pending, id)
+}
+
+// handle passes the reply received to the request waiting for it.
+// Replies to requests that already timed out are dropped.
+func (r *replies) handle(message *nsq.Message) error {
+	reply := broker.NewTraceMsgCarrierFromBytes(message.Body)
+	if reply == nil {
+		// Requeuing an invalid reply would never succeed
+		r.n.logger.Errorw("invalid reply received", "topic", r.name)
+		return nil
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	cReply, ok := r.pending[reply.Get(broker.HEADER_CORRELATION_ID)]
+	if !ok {
+		return nil
+	}
+
+	select {
+	case cReply <- reply:
+	default:
+	}
+
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 // Subscriber holds additional options for nsq subscription
 type subscriber struct {
 	channel string
+	dedup   broker.Deduplicator
 }
 
 // NewNsqSubscriber returns a new subscriber instance for NSQ subscription
@@ -28,3 +29,8 @@ func WithChannelName(name string) broker.SubscribeOption {
 		s.(*subscriber).channel = name
 	}
 }
+
+// SetDeduplicator sets the consumer-side deduplicator for the subscription
+func (s *subscriber) SetDeduplicator(d broker.Deduplicator) {
+	s.dedup = d
+}
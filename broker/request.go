@@ -0,0 +1,151 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	gs_errors "github.com/easeq/go-service/errors"
+)
+
+const (
+	// HEADER_REPLY_TO is the carrier header holding the topic to publish the reply to
+	HEADER_REPLY_TO = "Reply-To"
+	// HEADER_CORRELATION_ID is the carrier header that matches a reply to its request
+	HEADER_CORRELATION_ID = "Correlation-Id"
+	// HEADER_REPLY_ERROR is the carrier header holding the error returned by the responder
+	HEADER_REPLY_ERROR = "Reply-Error"
+
+	// DEFAULT_REQUEST_TIMEOUT is the time to wait for a reply when no timeout is provided
+	DEFAULT_REQUEST_TIMEOUT = 5 * time.Second
+)
+
+var (
+	// ErrRequestNotSupported returned when the broker doesn't implement Requester
+	ErrRequestNotSupported = errors.New("broker doesn't support request/reply")
+	// ErrRequestTimeout returned when no reply is received before the timeout
+	ErrRequestTimeout = errors.New("broker request timed out")
+	// ErrInvalidReply returned when the reply received cannot be decoded
+	ErrInvalidReply = errors.New("invalid reply received")
+)
+
+// ReplyError is returned to the requester when the responder fails to handle the request
+type ReplyError struct {
+	Message string
+}
+
+func (e *ReplyError) Error() string {
+	return fmt.Sprintf("reply error: %s", e.Message)
+}
+
+// ReplyHandler used by the responder
+type ReplyHandler interface {
+	// Reply handles the request message and returns the reply message
+	Reply(ctx context.Context, m *Message) (interface{}, error)
+}
+
+// Requester is implemented by the brokers that support request/reply
+type Requester interface {
+	// Request publishes a message and waits for the reply
+	Request(ctx context.Context, topic string, message interface{}, opts ...RequestOption) (*Message, error)
+	// Respond subscribes to a subject and replies to the requests received
+	Respond(ctx context.Context, topic string, handler ReplyHandler, opts ...SubscribeOption) error
+}
+
+// RequestOptions holds the options used while sending a request
+type RequestOptions struct {
+	// Timeout is the time to wait for the reply
+	Timeout time.Duration
+}
+
+// RequestOption to pass as arg while sending a request
+type RequestOption func(*RequestOptions)
+
+// NewRequestOptions returns the request options with the defaults set
+func NewRequestOptions(opts ...RequestOption) *RequestOptions {
+	o := &RequestOptions{
+		Timeout: DEFAULT_REQUEST_TIMEOUT,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// WithRequestTimeout sets the time to wait for the reply
+func WithRequestTimeout(timeout time.Duration) RequestOption {
+	return func(o *RequestOptions) {
+		o.Timeout = timeout
+	}
+}
+
+// Request sends the request using the broker if it supports request/reply
+func Request(
+	ctx context.Context,
+	b Broker,
+	topic string,
+	message interface{},
+	opts ...RequestOption,
+) (*Message, error) {
+	r, ok := b.(Requester)
+	if !ok {
+		return nil, ErrRequestNotSupported
+	}
+
+	return r.Request(ctx, topic, message, opts...)
+}
+
+// NewReplyCarrier returns the carrier for the reply to the given request.
+// The public message of the error returned by the reply handler, if any, is sent in the reply headers,
+// the internal errors are sent as errors.INTERNAL_MESSAGE.
+func NewReplyCarrier(request *TraceMsgCarrier, payload []byte, err error) *TraceMsgCarrier {
+	reply := NewTraceMsgCarrier(request.Get(HEADER_REPLY_TO), payload)
+	if id := request.Get(HEADER_CORRELATION_ID); id != "" {
+		reply.Set(HEADER_CORRELATION_ID, id)
+	}
+
+	if err != nil {
+		reply.Set(HEADER_REPLY_ERROR, gs_errors.FromError(err).Message)
+	}
+
+	return reply
+}
+
+// NewReplyMessage converts the reply carrier received to a Message
+func NewReplyMessage(reply *TraceMsgCarrier, raw interface{}) (*Message, error) {
+	if reply == nil {
+		return nil, ErrInvalidReply
+	}
+
+	if msg := reply.Get(HEADER_REPLY_ERROR); msg != "" {
+		return nil, &ReplyError{msg}
+	}
+
+	return &Message{
+		ID:   reply.MessageID(),
+		Body: reply.Message,
+		Extras: map[string]interface{}{
+			KEY_TRACE_MSG_CARRIER: reply,
+			KEY_BROKER_MSG:        raw,
+		},
+	}, nil
+}
+
+// HandleReply passes the request to the reply handler and returns the reply
+// encoded with Marshal, as the published messages are
+func HandleReply(ctx context.Context, handler ReplyHandler, m *Message) ([]byte, error) {
+	res, err := handler.Reply(ctx, m)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := Marshal(res)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling error: %v", err)
+	}
+
+	return payload, nil
+}
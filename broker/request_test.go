@@ -0,0 +1,98 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	gs_errors "github.com/easeq/go-service/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplyCarrier(t *testing.T) {
+	request := NewTraceMsgCarrier("test-topic", []byte(`{"q":1}`))
+	request.Set(HEADER_REPLY_TO, "test-reply-topic")
+	request.Set(HEADER_CORRELATION_ID, "test-correlation-id")
+
+	tests := []struct {
+		name    string
+		payload []byte
+		err     error
+		wantMsg string
+	}{
+		{
+			name:    "Reply",
+			payload: []byte(`{"a":1}`),
+		},
+		{
+			name:    "ReplyError",
+			err:     gs_errors.NotFound("test-error"),
+			wantMsg: "test-error",
+		},
+		{
+			name:    "InternalReplyError",
+			err:     errors.New("connection refused: db-1.internal:5432"),
+			wantMsg: gs_errors.INTERNAL_MESSAGE,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			reply := NewReplyCarrier(request, tt.payload, tt.err)
+			require.Equal("test-reply-topic", reply.Topic)
+			require.Equal("test-correlation-id", reply.Get(HEADER_CORRELATION_ID))
+
+			data, err := reply.Bytes()
+			require.NoError(err)
+
+			m, err := NewReplyMessage(NewTraceMsgCarrierFromBytes(data), nil)
+			if tt.err != nil {
+				var replyErr *ReplyError
+				require.True(errors.As(err, &replyErr))
+				require.Equal(tt.wantMsg, replyErr.Message)
+				require.Nil(m)
+				return
+			}
+
+			require.NoError(err)
+			require.Equal(tt.payload, m.Body)
+		})
+	}
+}
+
+type replyFunc func(ctx context.Context, m *Message) (interface{}, error)
+
+func (f replyFunc) Reply(ctx context.Context, m *Message) (interface{}, error) {
+	return f(ctx, m)
+}
+
+func TestHandleReply(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply interface{}
+		want  []byte
+	}{
+		{
+			name:  "JSON",
+			reply: map[string]int{"a": 1},
+			want:  []byte(`{"a":1}`),
+		},
+		{
+			name:  "Payload",
+			reply: Payload("raw"),
+			want:  []byte("raw"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, err := HandleReply(context.TODO(), replyFunc(func(ctx context.Context, m *Message) (interface{}, error) {
+				return tt.reply, nil
+			}), &Message{})
+			require.NoError(t, err)
+			require.Equal(t, tt.want, payload)
+		})
+	}
+}
@@ -65,6 +65,60 @@ func (t *Trace) Subscribe(ctx context.Context, tm *TraceMsgCarrier, subscribe Su
 	return err
 }
 
+// Request adds tracer details to the request and records the responder's span on reply
+func (t *Trace) Request(ctx context.Context, tm *TraceMsgCarrier, request RequestCallback) (*TraceMsgCarrier, error) {
+	opName := fmt.Sprintf("%s.request %s", t.b.String(), tm.Topic)
+	_, span := t.start(ctx, tm, opName, trace.SpanKindClient, []attribute.KeyValue{
+		semconv.MessageTypeSent,
+	})
+	defer span.End()
+
+	reply, err := request(tm)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	if msg := reply.Get(HEADER_REPLY_ERROR); msg != "" {
+		span.SetStatus(codes.Error, msg)
+	}
+
+	// Link the responder's span, propagated back in the reply headers
+	replyCtx := t.propagator.Extract(context.Background(), reply)
+	if sc := trace.SpanContextFromContext(replyCtx); sc.IsValid() {
+		span.AddEvent("reply", trace.WithAttributes(
+			attribute.String("messaging.reply.trace_id", sc.TraceID().String()),
+			attribute.String("messaging.reply.span_id", sc.SpanID().String()),
+		))
+	}
+
+	return reply, nil
+}
+
+// Respond adds the tracer details to the context of the request handler and to the reply
+func (t *Trace) Respond(ctx context.Context, tm *TraceMsgCarrier, respond RespondCallback, send PublishCallback) error {
+	opName := fmt.Sprintf("%s.respond %s", t.b.String(), tm.Topic)
+	ctx, span := t.start(ctx, tm, opName, trace.SpanKindServer, []attribute.KeyValue{
+		semconv.MessageTypeReceived,
+	})
+	defer span.End()
+
+	payload, err := respond(ctx, tm)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	reply := NewReplyCarrier(tm, payload, err)
+	t.propagator.Inject(ctx, reply)
+
+	if err := send(reply); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	return nil
+}
+
 func (t *Trace) start(
 	ctx context.Context,
 	tm *TraceMsgCarrier,
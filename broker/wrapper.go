@@ -12,6 +12,8 @@ type Wrapper struct {
 
 type PublishCallback func(*TraceMsgCarrier) error
 type SubscribeCallback func(context.Context, *TraceMsgCarrier) error
+type RequestCallback func(*TraceMsgCarrier) (*TraceMsgCarrier, error)
+type RespondCallback func(context.Context, *TraceMsgCarrier) ([]byte, error)
 
 func NewWrapper(b Broker) *Wrapper {
 	return &Wrapper{b, NewTrace(b)}
@@ -34,9 +36,57 @@ func (w *Wrapper) Subscribe(ctx context.Context, topic string, tmBytes []byte, s
 		return errors.New("payload empty")
 	}
 
+	return w.SubscribeCarrier(ctx, tm, subscribe)
+}
+
+// SubscribeCarrier - handles the message of the carrier, e.g. built from the headers of a message
+// not published by go-service, and adds traceparent to the ctx if tracer is defined
+func (w *Wrapper) SubscribeCarrier(ctx context.Context, tm *TraceMsgCarrier, subscribe SubscribeCallback) error {
 	if w.trace == nil {
 		return subscribe(ctx, tm)
 	}
 
 	return w.trace.Subscribe(ctx, tm, subscribe)
 }
+
+// Request - sends a request with the traceparent and returns the reply if tracer is defined
+func (w *Wrapper) Request(ctx context.Context, topic string, payload []byte, request RequestCallback) (*TraceMsgCarrier, error) {
+	tm := NewTraceMsgCarrier(topic, payload)
+	if w.trace == nil {
+		return request(tm)
+	}
+
+	return w.trace.Request(ctx, tm, request)
+}
+
+// Respond - handles a request and sends the reply with the responder's traceparent if tracer is defined
+func (w *Wrapper) Respond(
+	ctx context.Context,
+	topic string,
+	tmBytes []byte,
+	respond RespondCallback,
+	send PublishCallback,
+) error {
+	tm := NewTraceMsgCarrierFromBytes(tmBytes)
+	if tm == nil {
+		return errors.New("payload empty")
+	}
+
+	// Only the public message of the error is sent to the requester, the full error is logged here
+	handle := respond
+	respond = func(ctx context.Context, tm *TraceMsgCarrier) ([]byte, error) {
+		payload, err := handle(ctx, tm)
+		if err != nil && w.b.Logger() != nil {
+			w.b.Logger().Errorw("reply handler error", "topic", topic, "err", err)
+		}
+
+		return payload, err
+	}
+
+	if w.trace == nil {
+		payload, err := respond(ctx, tm)
+		return send(NewReplyCarrier(tm, payload, err))
+	}
+
+	return w.trace.Respond(ctx, tm, respond, send)
+}
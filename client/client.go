@@ -15,14 +15,13 @@ const (
 var (
 	// ErrInvalidStream returned when the stream is invalid
 	ErrInvalidStream = errors.New("invalid client stream")
+	// ErrNotMocked returned by the generated client mocks for the methods without a mock function
+	ErrNotMocked = errors.New("method not mocked")
 )
 
 // CallFn function for Call method
 type CallFn func(conn pool.Connection) error
 
-// CallOption is used to pass client call options
-type CallOption interface{}
-
 // DialOption is used to pass clients' dial options
 type DialOption interface{}
 
@@ -45,14 +44,26 @@ type ServiceClient interface {
 	GetDialOptions() []DialOption
 }
 
+// Stream interface holds the stream methods independent of the message types,
+// it's embedded by the typed stream clients generated by protoc-gen-go-service
+type Stream interface {
+	// Close the sending side of the stream
+	CloseSend() error
+	// Header returns the header metadata received from the server
+	Header() (map[string][]string, error)
+	// Trailer returns the trailer metadata, available once the stream terminates
+	Trailer() map[string][]string
+	// Close client connection
+	CloseConn() error
+}
+
 // StreamClient interface is used by client implementation for streaming
 type StreamClient interface {
+	Stream
 	// Send request
 	Send(req interface{}) error
 	// Close send and receive response
 	CloseAndRecv(res interface{}) error
 	// Receive response
 	Recv(res interface{}) error
-	// Close client connection
-	CloseConn() error
 }
@@ -0,0 +1,71 @@
+package grpc
+
+import (
+	"math/rand"
+	"sync/atomic"
+
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/base"
+	"google.golang.org/grpc/balancer/roundrobin"
+)
+
+const (
+	// ROUND_ROBIN balancing policy picks the instances in turn
+	ROUND_ROBIN = roundrobin.Name
+	// LEAST_REQUEST balancing policy picks the instance with the fewest in-flight requests
+	// among two randomly chosen instances
+	LEAST_REQUEST = "least_request"
+)
+
+func init() {
+	balancer.Register(base.NewBalancerBuilder(
+		LEAST_REQUEST,
+		&leastRequestPickerBuilder{},
+		base.Config{HealthCheck: true},
+	))
+}
+
+type leastRequestPickerBuilder struct{}
+
+// Build returns a new least request picker for the ready sub connections
+func (b *leastRequestPickerBuilder) Build(info base.PickerBuildInfo) balancer.Picker {
+	if len(info.ReadySCs) == 0 {
+		return base.NewErrPicker(balancer.ErrNoSubConnAvailable)
+	}
+
+	subConns := make([]*leastRequestSubConn, 0, len(info.ReadySCs))
+	for sc := range info.ReadySCs {
+		subConns = append(subConns, &leastRequestSubConn{sc: sc})
+	}
+
+	return &leastRequestPicker{subConns}
+}
+
+// leastRequestSubConn keeps track of the in-flight requests of a sub connection
+type leastRequestSubConn struct {
+	sc       balancer.SubConn
+	inflight int64
+}
+
+type leastRequestPicker struct {
+	subConns []*leastRequestSubConn
+}
+
+// Pick picks the sub connection with fewer in-flight requests out of two random choices
+func (p *leastRequestPicker) Pick(info balancer.PickInfo) (balancer.PickResult, error) {
+	picked := p.subConns[rand.Intn(len(p.subConns))]
+	if len(p.subConns) > 1 {
+		other := p.subConns[rand.Intn(len(p.subConns))]
+		if atomic.LoadInt64(&other.inflight) < atomic.LoadInt64(&picked.inflight) {
+			picked = other
+		}
+	}
+
+	atomic.AddInt64(&picked.inflight, 1)
+	return balancer.PickResult{
+		SubConn: picked.sc,
+		Done: func(balancer.DoneInfo) {
+			atomic.AddInt64(&picked.inflight, -1)
+		},
+	}, nil
+}
@@ -0,0 +1,114 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	// ErrCircuitOpen returned without calling the service while its circuit breaker is open
+	ErrCircuitOpen = status.Error(codes.Unavailable, "circuit breaker open")
+)
+
+// breaker opens after a number of consecutive failed calls to a service,
+// and fails the calls fast until the timeout elapses.
+// A single call is then let through, closing the breaker if it succeeds.
+type breaker struct {
+	threshold int
+	timeout   time.Duration
+	failures  int
+	openedAt  time.Time
+	probing   bool
+	sync.Mutex
+}
+
+func newBreaker(threshold int, timeout time.Duration) *breaker {
+	return &breaker{threshold: threshold, timeout: timeout}
+}
+
+// allow returns true if the call can be made,
+// and probe is true if the call is the single call let through once the timeout elapsed
+func (b *breaker) allow() (allowed bool, probe bool) {
+	if b.threshold <= 0 {
+		return true, false
+	}
+
+	b.Lock()
+	defer b.Unlock()
+
+	if b.failures < b.threshold {
+		return true, false
+	}
+
+	if b.probing || time.Since(b.openedAt) < b.timeout {
+		return false, false
+	}
+
+	b.probing = true
+	return true, true
+}
+
+// record records the result of the call allowed.
+// Only the result of the probe lets another call through, the calls made before the breaker opened don't.
+func (b *breaker) record(err error, probe bool) {
+	if b.threshold <= 0 {
+		return
+	}
+
+	b.Lock()
+	defer b.Unlock()
+
+	if probe {
+		b.probing = false
+	}
+
+	if !failure(err) {
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openedAt = time.Now()
+	}
+}
+
+// failure returns true if the error means the service is unavailable or overloaded.
+// The errors without a status, e.g. the dial or the pool errors, are failures,
+// unless the call is cancelled by the caller.
+func failure(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	s, ok := status.FromError(err)
+	if !ok {
+		return true
+	}
+
+	switch s.Code() {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted:
+		return true
+	default:
+		return false
+	}
+}
+
+// breaker returns the circuit breaker of the service
+func (c *Grpc) breaker(service string) *breaker {
+	c.Lock()
+	defer c.Unlock()
+
+	b, ok := c.breakers[service]
+	if !ok {
+		b = newBreaker(c.BreakerThreshold, c.BreakerTimeout)
+		c.breakers[service] = b
+	}
+
+	return b
+}
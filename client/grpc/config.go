@@ -0,0 +1,109 @@
+package grpc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Netflix/go-env"
+	"github.com/easeq/go-service/component"
+	"google.golang.org/grpc/codes"
+)
+
+// Config holds the gRPC client configuration
+type Config struct {
+	// Balancer is the load balancing policy, round_robin or least_request
+	Balancer string `env:"GRPC_CLIENT_BALANCER,default=round_robin"`
+	// Timeout is the default deadline of the calls without one
+	Timeout time.Duration `env:"GRPC_CLIENT_TIMEOUT,default=10s"`
+	// MaxAttempts is the maximum number of attempts of the idempotent calls
+	MaxAttempts int `env:"GRPC_CLIENT_MAX_ATTEMPTS,default=3"`
+	// InitialBackoff is the time to wait before the first retry
+	InitialBackoff time.Duration `env:"GRPC_CLIENT_INITIAL_BACKOFF,default=100ms"`
+	// MaxBackoff is the maximum time to wait between retries
+	MaxBackoff time.Duration `env:"GRPC_CLIENT_MAX_BACKOFF,default=2s"`
+	// BackoffMultiplier is the factor by which the backoff grows after each retry
+	BackoffMultiplier float64 `env:"GRPC_CLIENT_BACKOFF_MULTIPLIER,default=2"`
+	// Jitter is the fraction of the backoff randomly added or removed
+	Jitter float64 `env:"GRPC_CLIENT_JITTER,default=0.2"`
+	// RetryableCodes is a comma separated list of the status codes retried, e.g. UNAVAILABLE
+	RetryableCodes string `env:"GRPC_CLIENT_RETRYABLE_CODES,default=UNAVAILABLE"`
+	// HedgingDelay is the delay after which another attempt of an idempotent call is sent, 0 disables hedging
+	HedgingDelay time.Duration `env:"GRPC_CLIENT_HEDGING_DELAY,default=0s"`
+	// BreakerThreshold is the number of consecutive failures opening the circuit breaker, 0 disables it
+	BreakerThreshold int `env:"GRPC_CLIENT_BREAKER_THRESHOLD,default=5"`
+	// BreakerTimeout is the time after which an open circuit breaker lets a call through
+	BreakerTimeout time.Duration `env:"GRPC_CLIENT_BREAKER_TIMEOUT,default=30s"`
+	// PropagateHeaders is a comma separated list of the incoming metadata keys propagated to the calls
+	PropagateHeaders string `env:"GRPC_CLIENT_PROPAGATE_HEADERS,default=x-request-id"`
+}
+
+// NewConfig returns the parsed config for the gRPC client from env
+func NewConfig() *Config {
+	c := new(Config)
+	component.NewConfig(c)
+
+	return c
+}
+
+// UnmarshalEnv env.EnvSet to Config
+func (c *Config) UnmarshalEnv(es env.EnvSet) error {
+	return env.Unmarshal(es, c)
+}
+
+// ServiceConfig returns the default service config of the client connections
+func (c *Config) ServiceConfig() string {
+	return fmt.Sprintf(`{"loadBalancingConfig":[{"%s":{}}]}`, c.Balancer)
+}
+
+// Policy returns the default call policy
+func (c *Config) Policy() Policy {
+	return Policy{
+		Timeout:           c.Timeout,
+		MaxAttempts:       c.MaxAttempts,
+		InitialBackoff:    c.InitialBackoff,
+		MaxBackoff:        c.MaxBackoff,
+		BackoffMultiplier: c.BackoffMultiplier,
+		Jitter:            c.Jitter,
+		RetryableCodes:    c.GetRetryableCodes(),
+		HedgingDelay:      c.HedgingDelay,
+	}
+}
+
+// GetRetryableCodes returns the status codes retried, the invalid codes are ignored
+func (c *Config) GetRetryableCodes() []codes.Code {
+	parsed := []codes.Code{}
+	for _, name := range strings.Split(c.RetryableCodes, ",") {
+		code, err := parseCode(name)
+		if err != nil {
+			continue
+		}
+
+		parsed = append(parsed, code)
+	}
+
+	return parsed
+}
+
+// parseCode returns the status code of the name, e.g. "UNAVAILABLE"
+func parseCode(name string) (codes.Code, error) {
+	var code codes.Code
+	if err := code.UnmarshalJSON([]byte(strconv.Quote(strings.TrimSpace(name)))); err != nil {
+		return code, fmt.Errorf("invalid status code %q", name)
+	}
+
+	return code, nil
+}
+
+// GetPropagateHeaders returns the metadata keys propagated to the calls
+func (c *Config) GetPropagateHeaders() []string {
+	headers := []string{}
+	for _, key := range strings.Split(c.PropagateHeaders, ",") {
+		if key = strings.ToLower(strings.TrimSpace(key)); key != "" {
+			headers = append(headers, key)
+		}
+	}
+
+	return headers
+}
@@ -3,6 +3,7 @@ package grpc
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sync"
 
 	"github.com/easeq/go-service/client"
@@ -11,6 +12,7 @@ import (
 	"github.com/easeq/go-service/pool"
 	"github.com/easeq/go-service/registry"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
 )
 
 const (
@@ -33,29 +35,54 @@ var (
 	ErrInvalidGrpcClient = errors.New("invalid GrpcClient")
 	// ErrInvalidStreamDescription returned when the variable passed is not of grpc.StreamDesc type
 	ErrInvalidStreamDescription = errors.New("invalid stream description")
-	// ErrInvalidFactoryConn returned when factory conn is invalid
+	// ErrInvalidFactoryConn returned when the connection created by the pool.Factory isn't a gRPC client connection
 	ErrInvalidFactoryConn = errors.New("invalid factory client connection")
+	// ErrInvalidCallOption returned when the call option is not supported by the gRPC client
+	ErrInvalidCallOption = errors.New("unsupported call option")
+	// ErrUnhealthyConn returned when the pooled client connection is shut down or failing
+	ErrUnhealthyConn = errors.New("client connection is shut down or failing")
 )
 
 // ClientOption to pass as arg while creating new service
 type ClientOption func(*Grpc)
 
-// Grpc client that holds the reference to the pool,
-// along with other configuration required to create the pool
+// Factory creates the pooled client connection of the address
+type Factory func(address string) (*grpc.ClientConn, error)
+
+// Grpc client holds one multiplexed client connection per service,
+// the service instances are resolved and load balanced using the service registry.
+// When a factory is provided, the connections are pooled per address instead.
 // It holds a reference to the service registry used by the service.
 type Grpc struct {
 	i         component.Initializer
 	logger    logger.Logger
-	pool      *pool.ConnectionPool
-	factory   pool.Factory
-	closeFunc pool.CloseFunc
-	Registry  registry.ServiceRegistry
+	pool      *pool.Pool[*grpc.ClientConn]
+	factory   Factory
+	closeFunc func(cc *grpc.ClientConn) error
+	poolOpts  []pool.Option
+	conns     map[string]*grpc.ClientConn
+	policies  map[string][]PolicyOption
+	breakers  map[string]*breaker
+	// interceptors registered with the client options, chained after the built-in ones
+	unaryInterceptors  []grpc.UnaryClientInterceptor
+	streamInterceptors []grpc.StreamClientInterceptor
+	tokenSource        TokenSource
+	chainOnce          sync.Once
+	unary              grpc.UnaryClientInterceptor
+	stream             grpc.StreamClientInterceptor
+	Registry           registry.ServiceRegistry
 	sync.RWMutex
+	*Config
 }
 
 // NewGrpc creates a new gRPC client
 func NewGrpc(opts ...ClientOption) *Grpc {
-	c := new(Grpc)
+	c := &Grpc{
+		conns:    make(map[string]*grpc.ClientConn),
+		policies: make(map[string][]PolicyOption),
+		breakers: make(map[string]*breaker),
+		Config:   NewConfig(),
+	}
 
 	for _, opt := range opts {
 		opt(c)
@@ -63,12 +90,21 @@ func NewGrpc(opts ...ClientOption) *Grpc {
 
 	c.i = NewInitializer(c)
 
-	c.pool = pool.NewPool(
-		pool.WithFactory(c.factory),
-		pool.WithSize(10),
-		pool.WithCloseFunc(c.closeFunc),
-		pool.WithLogger(c.logger),
-	)
+	if c.factory != nil {
+		opts := []pool.PoolOption[*grpc.ClientConn]{
+			pool.WithOptions[*grpc.ClientConn](append([]pool.Option{
+				pool.WithSize(10),
+				pool.WithLogger(c.logger),
+			}, c.poolOpts...)...),
+			pool.WithValidator(checkConn),
+		}
+
+		if c.closeFunc != nil {
+			opts = append(opts, pool.WithCloser(c.closeFunc))
+		}
+
+		c.pool = pool.New(c.factory, opts...)
+	}
 
 	return c
 }
@@ -80,43 +116,172 @@ func WithRegistry(registry registry.ServiceRegistry) ClientOption {
 	}
 }
 
-// WithFactory defines the client connection creation factory
+// WithBalancer sets the load balancing policy, ROUND_ROBIN or LEAST_REQUEST
+func WithBalancer(name string) ClientOption {
+	return func(c *Grpc) {
+		c.Balancer = name
+	}
+}
+
+// WithFactory defines the client connection creation factory.
+// The connections created by the factory are pooled per address,
+// dialing fails with ErrInvalidFactoryConn for the connections that aren't *grpc.ClientConn.
 func WithFactory(factory pool.Factory) ClientOption {
+	return WithConnFactory(func(address string) (*grpc.ClientConn, error) {
+		conn, err := factory(address)
+		if err != nil {
+			return nil, err
+		}
+
+		cc, ok := conn.(*grpc.ClientConn)
+		if !ok {
+			return nil, ErrInvalidFactoryConn
+		}
+
+		return cc, nil
+	})
+}
+
+// WithConnFactory defines the typed client connection creation factory.
+// The connections created by the factory are pooled per address.
+func WithConnFactory(factory Factory) ClientOption {
 	return func(c *Grpc) {
 		c.factory = factory
 	}
 }
 
-// WithCloseFunc passes the callback function to close the gRPC connection in the pool
+// WithCloseFunc passes the callback function to close the gRPC connection in the pool,
+// the connections are closed with Close by default
 func WithCloseFunc(closeFunc pool.CloseFunc) ClientOption {
+	return WithConnCloser(func(cc *grpc.ClientConn) error {
+		return closeFunc(cc)
+	})
+}
+
+// WithConnCloser passes the typed callback function to close the gRPC connection in the pool,
+// the connections are closed with Close by default
+func WithConnCloser(closer func(cc *grpc.ClientConn) error) ClientOption {
+	return func(c *Grpc) {
+		c.closeFunc = closer
+	}
+}
+
+// WithPoolOptions sets the options of the connection pool used with a factory,
+// e.g. the max active connections or the idle timeout
+func WithPoolOptions(opts ...pool.Option) ClientOption {
 	return func(c *Grpc) {
-		c.closeFunc = closeFunc
+		c.poolOpts = append(c.poolOpts, opts...)
+	}
+}
+
+// checkConn validates the pooled client connections before reuse
+func checkConn(cc *grpc.ClientConn) error {
+	switch cc.GetState() {
+	case connectivity.Shutdown, connectivity.TransientFailure:
+		return ErrUnhealthyConn
 	}
+
+	return nil
 }
 
-// Dial creates/gets a connection from the pool using the address from the service registry
+// Dial returns the client connection of the service.
+// The client connection is created on the first dial and shared by all the calls,
+// unless a factory is provided and the connection is borrowed from the pool.
 func (c *Grpc) Dial(name string, opts ...client.DialOption) (pool.Connection, error) {
-	address := c.Registry.ConnectionString(name, defaultScheme)
-	c.logger.Debugf("dial: %s", address)
-	return c.pool.Get(address)
+	sc, err := c.dial(name, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return sc, nil
 }
 
-// Get client conn
-func (c *Grpc) GetConnFromPool(serviceName string) (pool.Connection, *grpc.ClientConn, error) {
-	pcc, err := c.Dial(serviceName)
+// dial returns the shared client connection of the service, or a pooled one when a factory is provided
+func (c *Grpc) dial(name string, opts ...client.DialOption) (*serviceConn, error) {
+	if c.pool != nil {
+		address := c.Registry.ConnectionString(name, defaultScheme)
+		c.logger.Debugf("dial: %s", address)
+		pc, err := c.pool.Get(address)
+		if err != nil {
+			return nil, err
+		}
+
+		return &serviceConn{name: address, cc: pc.Conn(), pc: pc}, nil
+	}
+
+	c.RLock()
+	cc, ok := c.conns[name]
+	c.RUnlock()
+	if ok {
+		return &serviceConn{name: name, cc: cc}, nil
+	}
+
+	dialOpts := []grpc.DialOption{
+		grpc.WithInsecure(),
+		grpc.WithResolvers(NewResolverBuilder(c.Registry, c.logger)),
+		grpc.WithDefaultServiceConfig(c.ServiceConfig()),
+	}
+
+	for _, opt := range opts {
+		dialOpt, ok := opt.(grpc.DialOption)
+		if !ok {
+			return nil, ErrInvalidDialOptions
+		}
+
+		dialOpts = append(dialOpts, dialOpt)
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	// The connection may have been created while waiting for the lock
+	if cc, ok := c.conns[name]; ok {
+		return &serviceConn{name: name, cc: cc}, nil
+	}
+
+	target := fmt.Sprintf("%s:///%s", SCHEME, name)
+	c.logger.Debugf("dial: %s", target)
+	cc, err := grpc.Dial(target, dialOpts...)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	cc, ok := pcc.Conn().(*grpc.ClientConn)
-	if !ok {
-		return nil, nil, ErrInvalidFactoryConn
+	c.conns[name] = cc
+	return &serviceConn{name: name, cc: cc}, nil
+}
+
+// Close closes the client connections of all the services
+func (c *Grpc) Close() error {
+	c.Lock()
+	conns := c.conns
+	c.conns = make(map[string]*grpc.ClientConn)
+	c.Unlock()
+
+	for name, cc := range conns {
+		if err := cc.Close(); err != nil {
+			c.logger.Errorw("client connection close error", "service", name, "error", err)
+		}
+	}
+
+	if c.pool != nil {
+		return c.pool.Close()
 	}
 
-	return pcc, cc, nil
+	return nil
+}
+
+// Get client conn
+func (c *Grpc) GetConnFromPool(serviceName string, opts ...client.DialOption) (pool.Connection, *grpc.ClientConn, error) {
+	sc, err := c.dial(serviceName, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return sc, sc.cc, nil
 }
 
-// Call gRPC method
+// Call gRPC method following the policy of the method.
+// The call fails fast while the circuit breaker of the service is open.
 func (c *Grpc) Call(
 	ctx context.Context,
 	sc client.ServiceClient,
@@ -125,23 +290,36 @@ func (c *Grpc) Call(
 	res interface{},
 	opts ...client.CallOption,
 ) error {
-	pcc, cc, err := c.GetConnFromPool(sc.GetServiceName())
+	policy, callOpts, err := c.policy(sc.GetServiceName(), method, opts)
 	if err != nil {
-		c.logger.Errorf("conn error: %s", err)
 		return err
 	}
 
-	defer pcc.Close()
+	b := c.breaker(sc.GetServiceName())
+	allowed, probe := b.allow()
+	if !allowed {
+		return ErrCircuitOpen
+	}
 
-	callOpts := make([]grpc.CallOption, len(opts))
-	for i, opt := range opts {
-		callOpts[i] = opt.(grpc.CallOption)
+	pcc, cc, err := c.GetConnFromPool(sc.GetServiceName(), sc.GetDialOptions()...)
+	if err != nil {
+		c.logger.Errorf("conn error: %s", err)
+		b.record(err, probe)
+		return err
 	}
 
-	return cc.Invoke(ctx, method, req, res, callOpts...)
+	defer pcc.Close()
+
+	c.chain()
+	err = policy.invoke(ctx, res, func(ctx context.Context, res interface{}) error {
+		return c.unary(ctx, method, req, res, cc, invoke, callOpts...)
+	})
+	b.record(err, probe)
+	return err
 }
 
-// Stream gRPC method
+// Stream gRPC method.
+// The connection is released once the stream terminates or its context is cancelled.
 func (c *Grpc) Stream(
 	ctx context.Context,
 	sc client.ServiceClient,
@@ -150,39 +328,81 @@ func (c *Grpc) Stream(
 	req interface{},
 	opts ...client.CallOption,
 ) (client.StreamClient, error) {
-	pcc, cc, err := c.GetConnFromPool(sc.GetServiceName())
+	// Streams are neither retried nor given a default deadline
+	_, callOpts, err := c.policy(sc.GetServiceName(), method, opts)
 	if err != nil {
-		c.logger.Errorf("conn error: %s", err)
 		return nil, err
 	}
 
-	callOpts := make([]grpc.CallOption, len(opts))
-	for i, opt := range opts {
-		callOpts[i] = opt.(grpc.CallOption)
-	}
-
 	serviceDesc, ok := desc.(*grpc.StreamDesc)
 	if !ok {
 		return nil, ErrInvalidStreamDescription
 	}
 
-	stream, err := cc.NewStream(ctx, serviceDesc, method, callOpts...)
+	b := c.breaker(sc.GetServiceName())
+	allowed, probe := b.allow()
+	if !allowed {
+		return nil, ErrCircuitOpen
+	}
+
+	pcc, cc, err := c.GetConnFromPool(sc.GetServiceName(), sc.GetDialOptions()...)
 	if err != nil {
+		c.logger.Errorf("conn error: %s", err)
+		b.record(err, probe)
 		return nil, err
 	}
 
-	gs := &GrpcStreamClient{stream, pcc}
+	c.chain()
+	stream, err := c.stream(ctx, serviceDesc, cc, method, newStream, callOpts...)
+	b.record(err, probe)
+	if err != nil {
+		pcc.Close()
+		return nil, err
+	}
+
+	gs := newStreamClient(stream, serviceDesc, pcc)
 	if req == nil {
 		return gs, nil
 	}
 
-	if err := gs.stream.SendMsg(req); err != nil {
+	if err := gs.Send(req); err != nil {
+		gs.CloseConn()
 		return nil, err
 	}
 
+	// The request is the only message sent on the server streams
+	if !serviceDesc.ClientStreams {
+		if err := gs.CloseSend(); err != nil {
+			gs.CloseConn()
+			return nil, err
+		}
+	}
+
 	return gs, nil
 }
 
+// invoke is the unary invoker ending the interceptor chain
+func invoke(
+	ctx context.Context,
+	method string,
+	req, reply interface{},
+	cc *grpc.ClientConn,
+	opts ...grpc.CallOption,
+) error {
+	return cc.Invoke(ctx, method, req, reply, opts...)
+}
+
+// newStream is the streamer ending the interceptor chain
+func newStream(
+	ctx context.Context,
+	desc *grpc.StreamDesc,
+	cc *grpc.ClientConn,
+	method string,
+	opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	return cc.NewStream(ctx, desc, method, opts...)
+}
+
 func (c *Grpc) HasInitializer() bool {
 	return true
 }
@@ -191,40 +411,31 @@ func (g *Grpc) Initializer() component.Initializer {
 	return g.i
 }
 
-// GrpcStreamClient is the gRPC client that allows streaming. It holds the stream and the connection to the gRPC server.
-type GrpcStreamClient struct {
-	stream grpc.ClientStream
-	conn   pool.Connection
+// serviceConn is the client connection of a service.
+// The multiplexed connection is shared by all the calls, so closing it is a no-op,
+// while the pooled connection is released back to the pool.
+type serviceConn struct {
+	name string
+	cc   *grpc.ClientConn
+	pc   *pool.Conn[*grpc.ClientConn]
 }
 
-// Recv receive a message from the stream
-func (sc *GrpcStreamClient) Recv(res interface{}) error {
-	if err := sc.stream.RecvMsg(res); err != nil {
-		return err
-	}
-
-	return nil
+// Address returns the target service name, or the address of the pooled connection
+func (sc *serviceConn) Address() string {
+	return sc.name
 }
 
-// Send sends a message
-func (sc *GrpcStreamClient) Send(req interface{}) error {
-	return sc.stream.SendMsg(req)
+// Conn returns the gRPC client connection
+func (sc *serviceConn) Conn() pool.FactoryConn {
+	return sc.cc
 }
 
-// CloseAndRecv first close the server stream and receives messages on the client stream
-func (sc *GrpcStreamClient) CloseAndRecv(res interface{}) error {
-	if err := sc.stream.CloseSend(); err != nil {
-		return err
-	}
-
-	if err := sc.stream.RecvMsg(res); err != nil {
-		return err
+// Close releases the pooled connection,
+// the multiplexed client connection is closed when the client stops
+func (sc *serviceConn) Close() error {
+	if sc.pc != nil {
+		return sc.pc.Close()
 	}
 
 	return nil
 }
-
-// CloseConn closes the connection in the pool if the pool is full or adds it back to the pool
-func (sc *GrpcStreamClient) CloseConn() error {
-	return sc.conn.Close()
-}
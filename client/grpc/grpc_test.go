@@ -0,0 +1,203 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/easeq/go-service/component"
+	"github.com/easeq/go-service/logger"
+	"github.com/easeq/go-service/pool"
+	"github.com/easeq/go-service/registry"
+	"github.com/easeq/go-service/server"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type nopLogger struct {
+	logger.Logger
+}
+
+func (nopLogger) Debugf(template string, args ...interface{}) {}
+
+func (nopLogger) Debugw(message string, args ...interface{}) {}
+
+func (nopLogger) Errorw(message string, args ...interface{}) {}
+
+// staticWatcher returns the instances once and blocks until stopped
+type staticWatcher struct {
+	ctx       context.Context
+	cancel    context.CancelFunc
+	instances []*registry.Instance
+	sent      bool
+}
+
+func (w *staticWatcher) Next() ([]*registry.Instance, error) {
+	if !w.sent {
+		w.sent = true
+		return w.instances, nil
+	}
+
+	<-w.ctx.Done()
+	return nil, registry.ErrWatcherStopped
+}
+
+func (w *staticWatcher) Stop() {
+	w.cancel()
+}
+
+type staticRegistry struct {
+	instances []*registry.Instance
+}
+
+func (r *staticRegistry) Register(ctx context.Context, server server.Server) error { return nil }
+
+func (r *staticRegistry) Address() string { return "" }
+
+func (r *staticRegistry) ConnectionString(args ...interface{}) string { return "" }
+
+func (r *staticRegistry) Resolve(ctx context.Context, name string) ([]*registry.Instance, error) {
+	return r.instances, nil
+}
+
+func (r *staticRegistry) Watch(ctx context.Context, name string) (registry.Watcher, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &staticWatcher{ctx: ctx, cancel: cancel, instances: r.instances}, nil
+}
+
+func (r *staticRegistry) ToString() string { return "static" }
+
+func (r *staticRegistry) HasInitializer() bool { return false }
+
+func (r *staticRegistry) Initializer() component.Initializer { return nil }
+
+// startServer starts a gRPC health server counting the requests received
+func startServer(t *testing.T, calls *int64) *registry.Instance {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := grpc.NewServer(grpc.UnaryInterceptor(func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		atomic.AddInt64(calls, 1)
+		return handler(ctx, req)
+	}))
+	grpc_health_v1.RegisterHealthServer(s, health.NewServer())
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	host, port, err := net.SplitHostPort(lis.Addr().String())
+	require.NoError(t, err)
+	p, err := strconv.Atoi(port)
+	require.NoError(t, err)
+
+	return &registry.Instance{Address: host, Port: p, Health: registry.HEALTH_PASSING}
+}
+
+func TestDialBalancer(t *testing.T) {
+	for _, balancer := range []string{ROUND_ROBIN, LEAST_REQUEST} {
+		t.Run(balancer, func(t *testing.T) {
+			calls := make([]int64, 2)
+			r := &staticRegistry{instances: []*registry.Instance{
+				startServer(t, &calls[0]),
+				startServer(t, &calls[1]),
+			}}
+
+			c := NewGrpc(WithRegistry(r), WithBalancer(balancer))
+			c.logger = nopLogger{}
+			defer c.Close()
+
+			conn, err := c.Dial("test-service")
+			require.NoError(t, err)
+
+			again, err := c.Dial("test-service")
+			require.NoError(t, err)
+			require.Same(t, conn.Conn(), again.Conn())
+
+			// The requests are spread over both the instances once they are connected
+			hc := grpc_health_v1.NewHealthClient(conn.Conn().(*grpc.ClientConn))
+			require.Eventually(t, func() bool {
+				_, err := hc.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{}, grpc.WaitForReady(true))
+				require.NoError(t, err)
+
+				return atomic.LoadInt64(&calls[0]) > 0 && atomic.LoadInt64(&calls[1]) > 0
+			}, 5*time.Second, time.Millisecond)
+		})
+	}
+}
+
+func TestDialPool(t *testing.T) {
+	var calls int64
+	instance := startServer(t, &calls)
+
+	c := NewGrpc(
+		WithRegistry(&staticRegistry{}),
+		WithConnFactory(func(address string) (*grpc.ClientConn, error) {
+			return grpc.Dial(net.JoinHostPort(instance.Address, strconv.Itoa(instance.Port)), grpc.WithInsecure())
+		}),
+	)
+	c.logger = nopLogger{}
+	defer c.Close()
+
+	for i := 0; i < 2; i++ {
+		err := c.Call(
+			context.Background(),
+			testServiceClient{},
+			"/grpc.health.v1.Health/Check",
+			&grpc_health_v1.HealthCheckRequest{},
+			&grpc_health_v1.HealthCheckResponse{},
+		)
+		require.NoError(t, err)
+	}
+
+	// The connection is released after each call and reused
+	require.Equal(t, int64(2), atomic.LoadInt64(&calls))
+	require.Equal(t, pool.Stats{Idle: 1, Created: 1}, c.pool.Stats()[""])
+}
+
+func TestDialPoolFactory(t *testing.T) {
+	var calls int64
+	instance := startServer(t, &calls)
+
+	var closed int64
+	c := NewGrpc(
+		WithRegistry(&staticRegistry{}),
+		WithFactory(func(address string) (pool.FactoryConn, error) {
+			return grpc.Dial(net.JoinHostPort(instance.Address, strconv.Itoa(instance.Port)), grpc.WithInsecure())
+		}),
+		WithCloseFunc(func(conn interface{}) error {
+			atomic.AddInt64(&closed, 1)
+			return conn.(*grpc.ClientConn).Close()
+		}),
+	)
+	c.logger = nopLogger{}
+
+	conn, cc, err := c.GetConnFromPool("test-service")
+	require.NoError(t, err)
+	require.NotNil(t, cc)
+	require.NoError(t, conn.Close())
+
+	require.NoError(t, c.Close())
+	require.Equal(t, int64(1), atomic.LoadInt64(&closed))
+
+	// The connections of another type aren't pooled
+	c = NewGrpc(
+		WithRegistry(&staticRegistry{}),
+		WithFactory(func(address string) (pool.FactoryConn, error) {
+			return "invalid", nil
+		}),
+	)
+	c.logger = nopLogger{}
+	defer c.Close()
+
+	_, _, err = c.GetConnFromPool("test-service")
+	require.ErrorIs(t, err, ErrInvalidFactoryConn)
+}
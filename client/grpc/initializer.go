@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/easeq/go-service/logger"
+	"github.com/easeq/go-service/registry"
 )
 
 type Initializer struct {
@@ -22,6 +23,10 @@ func (i *Initializer) AddDependency(dep interface{}) error {
 	switch v := dep.(type) {
 	case logger.Logger:
 		i.g.logger = v
+	case registry.ServiceRegistry:
+		if i.g.Registry == nil {
+			i.g.Registry = v
+		}
 	}
 
 	return nil
@@ -30,7 +35,7 @@ func (i *Initializer) AddDependency(dep interface{}) error {
 // Dependencies returns the string names of service components
 // that are required as dependencies for this component
 func (i *Initializer) Dependencies() []string {
-	return []string{logger.LOGGER}
+	return []string{logger.LOGGER, registry.REGISTRY}
 }
 
 // CanRun returns true if the component has anything to Run
@@ -44,13 +49,12 @@ func (i *Initializer) Run(ctx context.Context) error {
 	return nil
 }
 
-// CanRun returns true if the component has anything to Run
+// CanStop returns true if the component has anything to Stop
 func (i *Initializer) CanStop() bool {
-	return false
+	return true
 }
 
-// Stop - stops the running
+// Stop - closes the client connections
 func (i *Initializer) Stop(ctx context.Context) error {
-	i.g.logger.Infow("Unimplemented")
-	return nil
+	return i.g.Close()
 }
@@ -0,0 +1,425 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/easeq/go-service/logger"
+	"github.com/easeq/go-service/tracer"
+	"go.opentelemetry.io/otel"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// HEADER_AUTHORIZATION is the metadata key of the auth token
+	HEADER_AUTHORIZATION = "authorization"
+)
+
+// TokenSource returns the auth token of the outgoing calls
+type TokenSource func(ctx context.Context) (string, error)
+
+// WithUnaryInterceptors adds unary interceptors, run in order after the built-in interceptors
+func WithUnaryInterceptors(interceptors ...grpc.UnaryClientInterceptor) ClientOption {
+	return func(c *Grpc) {
+		c.unaryInterceptors = append(c.unaryInterceptors, interceptors...)
+	}
+}
+
+// WithStreamInterceptors adds stream interceptors, run in order after the built-in interceptors
+func WithStreamInterceptors(interceptors ...grpc.StreamClientInterceptor) ClientOption {
+	return func(c *Grpc) {
+		c.streamInterceptors = append(c.streamInterceptors, interceptors...)
+	}
+}
+
+// WithTokenSource injects the token returned by the source as a bearer token in the outgoing calls
+func WithTokenSource(source TokenSource) ClientOption {
+	return func(c *Grpc) {
+		c.tokenSource = source
+	}
+}
+
+// chain builds the interceptor chains, the built-in interceptors
+// tracing, metadata propagation, auth and logging are run first
+func (c *Grpc) chain() {
+	c.chainOnce.Do(func() {
+		headers := c.GetPropagateHeaders()
+		unary := []grpc.UnaryClientInterceptor{
+			TracingUnaryClientInterceptor(),
+			MetadataUnaryClientInterceptor(headers...),
+		}
+		stream := []grpc.StreamClientInterceptor{
+			TracingStreamClientInterceptor(),
+			MetadataStreamClientInterceptor(headers...),
+		}
+
+		if c.tokenSource != nil {
+			unary = append(unary, AuthUnaryClientInterceptor(c.tokenSource))
+			stream = append(stream, AuthStreamClientInterceptor(c.tokenSource))
+		}
+
+		if c.logger != nil {
+			unary = append(unary, LoggingUnaryClientInterceptor(c.logger))
+			stream = append(stream, LoggingStreamClientInterceptor(c.logger))
+		}
+
+		c.unary = chainUnary(append(unary, c.unaryInterceptors...))
+		c.stream = chainStream(append(stream, c.streamInterceptors...))
+	})
+}
+
+// chainUnary returns a single interceptor running the interceptors in order
+func chainUnary(interceptors []grpc.UnaryClientInterceptor) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, next := interceptors[i], invoker
+			invoker = func(
+				ctx context.Context,
+				method string,
+				req, reply interface{},
+				cc *grpc.ClientConn,
+				opts ...grpc.CallOption,
+			) error {
+				return interceptor(ctx, method, req, reply, cc, next, opts...)
+			}
+		}
+
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// chainStream returns a single interceptor running the interceptors in order
+func chainStream(interceptors []grpc.StreamClientInterceptor) grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, next := interceptors[i], streamer
+			streamer = func(
+				ctx context.Context,
+				desc *grpc.StreamDesc,
+				cc *grpc.ClientConn,
+				method string,
+				opts ...grpc.CallOption,
+			) (grpc.ClientStream, error) {
+				return interceptor(ctx, desc, cc, method, next, opts...)
+			}
+		}
+
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+}
+
+// metadataCarrier adapts the outgoing metadata to propagation.TextMapCarrier
+type metadataCarrier struct {
+	md metadata.MD
+}
+
+// Get returns the first value of the key
+func (mc metadataCarrier) Get(key string) string {
+	values := mc.md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+// Set sets the value of the key
+func (mc metadataCarrier) Set(key string, value string) {
+	mc.md.Set(key, value)
+}
+
+// Keys returns the metadata keys
+func (mc metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(mc.md))
+	for k := range mc.md {
+		keys = append(keys, k)
+	}
+
+	return keys
+}
+
+// startSpan starts the client span of the call and injects it in the outgoing metadata
+func startSpan(ctx context.Context, method string) (context.Context, trace.Span) {
+	service, name := splitMethod(method)
+	ctx, span := otel.GetTracerProvider().Tracer(tracer.DEFAULT_TRACER_NAME).Start(
+		ctx,
+		strings.TrimPrefix(method, "/"),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.RPCSystemKey.String("grpc"),
+			semconv.RPCServiceKey.String(service),
+			semconv.RPCMethodKey.String(name),
+		),
+	)
+
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+
+	otel.GetTextMapPropagator().Inject(ctx, metadataCarrier{md})
+	return metadata.NewOutgoingContext(ctx, md), span
+}
+
+// endSpan records the status of the call and ends the span
+func endSpan(span trace.Span, err error) {
+	s, _ := status.FromError(err)
+	span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(s.Code())))
+	if err != nil {
+		span.SetStatus(otelcodes.Error, s.Message())
+	}
+
+	span.End()
+}
+
+// splitMethod splits the full method name /package.Service/Method into service and method
+func splitMethod(method string) (string, string) {
+	method = strings.TrimPrefix(method, "/")
+	if i := strings.LastIndex(method, "/"); i >= 0 {
+		return method[:i], method[i+1:]
+	}
+
+	return "", method
+}
+
+// TracingUnaryClientInterceptor starts a client span for the call and propagates it to the server
+func TracingUnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		ctx, span := startSpan(ctx, method)
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		endSpan(span, err)
+		return err
+	}
+}
+
+// TracingStreamClientInterceptor starts a client span for the stream and propagates it to the server.
+// The span ends when the stream is closed by the server or fails.
+func TracingStreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		ctx, span := startSpan(ctx, method)
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			endSpan(span, err)
+			return nil, err
+		}
+
+		return &tracedStream{ClientStream: stream, span: span}, nil
+	}
+}
+
+// tracedStream ends the span of the stream on the first receive error
+type tracedStream struct {
+	grpc.ClientStream
+	span  trace.Span
+	ended bool
+}
+
+// RecvMsg receives a message and ends the span when the stream is done
+func (s *tracedStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil && !s.ended {
+		s.ended = true
+		if errors.Is(err, io.EOF) {
+			endSpan(s.span, nil)
+		} else {
+			endSpan(s.span, err)
+		}
+	}
+
+	return err
+}
+
+// propagate copies the incoming metadata keys to the outgoing metadata,
+// the keys already set on the outgoing metadata are kept
+func propagate(ctx context.Context, keys []string) context.Context {
+	in, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+
+	out, _ := metadata.FromOutgoingContext(ctx)
+	pairs := []string{}
+	for _, key := range keys {
+		if len(out.Get(key)) > 0 {
+			continue
+		}
+
+		for _, value := range in.Get(key) {
+			pairs = append(pairs, key, value)
+		}
+	}
+
+	if len(pairs) == 0 {
+		return ctx
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, pairs...)
+}
+
+// MetadataUnaryClientInterceptor propagates the incoming metadata keys to the call
+func MetadataUnaryClientInterceptor(keys ...string) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		return invoker(propagate(ctx, keys), method, req, reply, cc, opts...)
+	}
+}
+
+// MetadataStreamClientInterceptor propagates the incoming metadata keys to the stream
+func MetadataStreamClientInterceptor(keys ...string) grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		return streamer(propagate(ctx, keys), desc, cc, method, opts...)
+	}
+}
+
+// authorize adds the token returned by the source to the outgoing metadata
+func authorize(ctx context.Context, source TokenSource) (context.Context, error) {
+	token, err := source(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("auth token error: %w", err)
+	}
+
+	if token == "" {
+		return ctx, nil
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, HEADER_AUTHORIZATION, "Bearer "+token), nil
+}
+
+// AuthUnaryClientInterceptor injects the token returned by the source in the call
+func AuthUnaryClientInterceptor(source TokenSource) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		ctx, err := authorize(ctx, source)
+		if err != nil {
+			return err
+		}
+
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// AuthStreamClientInterceptor injects the token returned by the source in the stream
+func AuthStreamClientInterceptor(source TokenSource) grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		ctx, err := authorize(ctx, source)
+		if err != nil {
+			return nil, err
+		}
+
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+}
+
+// LoggingUnaryClientInterceptor logs the calls and their errors
+func LoggingUnaryClientInterceptor(l logger.Logger) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		log(l, "call", method, start, err)
+		return err
+	}
+}
+
+// LoggingStreamClientInterceptor logs the streams opened and their errors
+func LoggingStreamClientInterceptor(l logger.Logger) grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		start := time.Now()
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		log(l, "stream", method, start, err)
+		return stream, err
+	}
+}
+
+func log(l logger.Logger, msg string, method string, start time.Time, err error) {
+	kv := []interface{}{
+		"method", method,
+		"code", status.Code(err).String(),
+		"duration", time.Since(start),
+	}
+
+	if err != nil {
+		l.Errorw(msg+" error", append(kv, "error", err)...)
+		return
+	}
+
+	l.Debugw(msg, kv...)
+}
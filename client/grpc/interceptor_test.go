@@ -0,0 +1,183 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/easeq/go-service/client"
+	"github.com/easeq/go-service/registry"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+)
+
+type testServiceClient struct{}
+
+func (testServiceClient) GetServiceName() string { return "test-service" }
+
+func (testServiceClient) GetDialOptions() []client.DialOption { return nil }
+
+// startMetadataServer starts a gRPC health server sending the metadata received on mds
+func startMetadataServer(t *testing.T, mds chan<- metadata.MD) *registry.Instance {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := grpc.NewServer(grpc.UnaryInterceptor(func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		mds <- md
+		return handler(ctx, req)
+	}))
+	grpc_health_v1.RegisterHealthServer(s, health.NewServer())
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	host, port, err := net.SplitHostPort(lis.Addr().String())
+	require.NoError(t, err)
+	p, err := strconv.Atoi(port)
+	require.NoError(t, err)
+
+	return &registry.Instance{Address: host, Port: p, Health: registry.HEALTH_PASSING}
+}
+
+func TestCallOptions(t *testing.T) {
+	c := NewGrpc()
+
+	policy, callOpts, err := c.policy("test-service", "/test/Method", []client.CallOption{
+		client.Idempotent(),
+		client.WithMaxAttempts(5),
+		WithCallOptions(grpc.WaitForReady(true)),
+	})
+	require.NoError(t, err)
+	require.True(t, policy.Idempotent)
+	require.Equal(t, 5, policy.MaxAttempts)
+	require.Len(t, callOpts, 1)
+
+	// The options passed later override the earlier ones
+	policy, _, err = c.policy("test-service", "/test/Method", []client.CallOption{
+		client.WithTimeout(time.Second),
+		client.WithMaxAttempts(3),
+		client.WithRetryableCodes("UNAVAILABLE", "DEADLINE_EXCEEDED"),
+		WithHedging(time.Millisecond),
+		client.WithTimeout(2 * time.Second),
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2*time.Second, policy.Timeout)
+	require.Equal(t, 3, policy.MaxAttempts)
+	require.Equal(t, []codes.Code{codes.Unavailable, codes.DeadlineExceeded}, policy.RetryableCodes)
+	require.Equal(t, time.Millisecond, policy.HedgingDelay)
+
+	_, _, err = c.policy("test-service", "/test/Method", []client.CallOption{
+		client.WithOption("unsupported"),
+	})
+	require.ErrorIs(t, err, ErrInvalidCallOption)
+
+	// An invalid code doesn't silently disable the retries
+	_, _, err = c.policy("test-service", "/test/Method", []client.CallOption{
+		client.WithRetryableCodes("UNAVAILABLE", "INVALID"),
+	})
+	require.ErrorIs(t, err, ErrInvalidCallOption)
+}
+
+func TestPolicies(t *testing.T) {
+	c := NewGrpc(
+		WithPolicy("test-service", Policy{Timeout: 2 * time.Second, Idempotent: true}),
+		WithPolicy("/test/Method", Policy{MaxAttempts: 5}),
+	)
+	defaults := c.Policy()
+
+	// The service policy keeps the defaults it doesn't set
+	policy, _, err := c.policy("test-service", "/test/Other", nil)
+	require.NoError(t, err)
+	require.Equal(t, 2*time.Second, policy.Timeout)
+	require.True(t, policy.Idempotent)
+	require.Equal(t, defaults.MaxAttempts, policy.MaxAttempts)
+	require.Equal(t, defaults.RetryableCodes, policy.RetryableCodes)
+	require.Equal(t, defaults.InitialBackoff, policy.InitialBackoff)
+
+	// The method policy is set over the service policy
+	policy, _, err = c.policy("test-service", "/test/Method", nil)
+	require.NoError(t, err)
+	require.Equal(t, 2*time.Second, policy.Timeout)
+	require.Equal(t, 5, policy.MaxAttempts)
+	require.Equal(t, defaults.RetryableCodes, policy.RetryableCodes)
+}
+
+func TestPolicyOptions(t *testing.T) {
+	c := NewGrpc(
+		WithPolicy("test-service", Policy{Jitter: 0.5, HedgingDelay: time.Second, Idempotent: true}),
+		WithPolicyOptions("/test/Method", func(p *Policy) {
+			p.Jitter = 0
+			p.HedgingDelay = 0
+			p.Idempotent = false
+		}),
+	)
+
+	policy, _, err := c.policy("test-service", "/test/Other", nil)
+	require.NoError(t, err)
+	require.Equal(t, 0.5, policy.Jitter)
+	require.Equal(t, time.Second, policy.HedgingDelay)
+	require.True(t, policy.Idempotent)
+
+	// The method policy turns off the fields set by the service policy
+	policy, _, err = c.policy("test-service", "/test/Method", nil)
+	require.NoError(t, err)
+	require.Zero(t, policy.Jitter)
+	require.Zero(t, policy.HedgingDelay)
+	require.False(t, policy.Idempotent)
+	require.Equal(t, c.Policy().MaxAttempts, policy.MaxAttempts)
+}
+
+func TestInterceptors(t *testing.T) {
+	mds := make(chan metadata.MD, 1)
+	r := &staticRegistry{instances: []*registry.Instance{startMetadataServer(t, mds)}}
+
+	var intercepted []string
+	c := NewGrpc(
+		WithRegistry(r),
+		WithTokenSource(func(ctx context.Context) (string, error) {
+			return "token", nil
+		}),
+		WithUnaryInterceptors(func(
+			ctx context.Context,
+			method string,
+			req, reply interface{},
+			cc *grpc.ClientConn,
+			invoker grpc.UnaryInvoker,
+			opts ...grpc.CallOption,
+		) error {
+			// The built-in interceptors are run first
+			md, _ := metadata.FromOutgoingContext(ctx)
+			intercepted = append(intercepted, md.Get(HEADER_AUTHORIZATION)...)
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}),
+	)
+	c.logger = nopLogger{}
+	defer c.Close()
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "req-1"))
+	err := c.Call(
+		ctx,
+		testServiceClient{},
+		"/grpc.health.v1.Health/Check",
+		&grpc_health_v1.HealthCheckRequest{},
+		&grpc_health_v1.HealthCheckResponse{},
+		WithCallOptions(grpc.WaitForReady(true)),
+	)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Bearer token"}, intercepted)
+
+	md := <-mds
+	require.Equal(t, []string{"Bearer token"}, md.Get(HEADER_AUTHORIZATION))
+	require.Equal(t, []string{"req-1"}, md.Get("x-request-id"))
+}
@@ -0,0 +1,298 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/easeq/go-service/client"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Policy defines how the calls to a service or a method are made.
+// Retries and hedging only apply to idempotent calls.
+type Policy struct {
+	// Timeout is the deadline of the calls without one
+	Timeout time.Duration
+	// MaxAttempts is the maximum number of attempts, including hedged ones
+	MaxAttempts int
+	// InitialBackoff is the time to wait before the first retry
+	InitialBackoff time.Duration
+	// MaxBackoff is the maximum time to wait between retries
+	MaxBackoff time.Duration
+	// BackoffMultiplier is the factor by which the backoff grows after each retry
+	BackoffMultiplier float64
+	// Jitter is the fraction of the backoff randomly added or removed
+	Jitter float64
+	// RetryableCodes are the status codes of the failed attempts retried
+	RetryableCodes []codes.Code
+	// HedgingDelay is the delay after which another attempt is sent, 0 disables hedging
+	HedgingDelay time.Duration
+	// Idempotent marks the calls safe to retry or hedge
+	Idempotent bool
+}
+
+// PolicyOption overrides the policy of a single call, or of a service or a method with WithPolicyOptions.
+// The timeout, the attempts and the retryable codes are set with the generic client call options,
+// e.g. client.WithTimeout.
+type PolicyOption func(*Policy)
+
+// WithHedging sends another attempt of the call after each delay until one succeeds
+func WithHedging(delay time.Duration) client.CallOption {
+	return client.WithOption(PolicyOption(func(p *Policy) {
+		p.HedgingDelay = delay
+	}))
+}
+
+// WithCallOptions passes the gRPC call options to the call
+func WithCallOptions(opts ...grpc.CallOption) client.CallOption {
+	return func(o *client.CallOptions) {
+		for _, opt := range opts {
+			o.Options = append(o.Options, opt)
+		}
+	}
+}
+
+// WithPolicy sets the policy of a service, or of a method using its full name, e.g. "/pkg.Service/Method".
+// The non-zero fields of the policy override the default policy from the config,
+// the method policy takes precedence over the service policy.
+// The fields are set to zero, e.g. to disable the jitter or the hedging, with WithPolicyOptions.
+func WithPolicy(name string, policy Policy) ClientOption {
+	return WithPolicyOptions(name, func(p *Policy) {
+		*p = p.merge(policy)
+	})
+}
+
+// WithPolicyOptions sets the fields of the policy of a service, or of a method using its full name,
+// including the zero values, e.g. func(p *Policy) { p.Idempotent = false }.
+// The options are applied in the order they are passed, after the options of WithPolicy passed before.
+func WithPolicyOptions(name string, opts ...PolicyOption) ClientOption {
+	return func(c *Grpc) {
+		c.policies[name] = append(c.policies[name], opts...)
+	}
+}
+
+// policy returns the policy of the method and the grpc call options,
+// after applying the call options passed.
+// It returns ErrInvalidCallOption for the options not supported by the gRPC client,
+// and for the invalid retryable codes.
+func (c *Grpc) policy(service string, method string, opts []client.CallOption) (Policy, []grpc.CallOption, error) {
+	policy := c.Policy()
+	for _, opt := range c.policies[service] {
+		opt(&policy)
+	}
+
+	for _, opt := range c.policies[method] {
+		opt(&policy)
+	}
+
+	o := client.NewCallOptions(opts...)
+	if o.Idempotent {
+		policy.Idempotent = true
+	}
+
+	if o.Timeout > 0 {
+		policy.Timeout = o.Timeout
+	}
+
+	if o.MaxAttempts > 0 {
+		policy.MaxAttempts = o.MaxAttempts
+	}
+
+	if len(o.RetryableCodes) > 0 {
+		policy.RetryableCodes = make([]codes.Code, 0, len(o.RetryableCodes))
+		for _, name := range o.RetryableCodes {
+			code, err := parseCode(name)
+			if err != nil {
+				return policy, nil, fmt.Errorf("%w: %v", ErrInvalidCallOption, err)
+			}
+
+			policy.RetryableCodes = append(policy.RetryableCodes, code)
+		}
+	}
+
+	callOpts := make([]grpc.CallOption, 0, len(o.Options))
+	for _, opt := range o.Options {
+		switch v := opt.(type) {
+		case PolicyOption:
+			v(&policy)
+		case grpc.CallOption:
+			callOpts = append(callOpts, v)
+		default:
+			return policy, nil, fmt.Errorf("%w: %T", ErrInvalidCallOption, opt)
+		}
+	}
+
+	return policy, callOpts, nil
+}
+
+// merge returns the policy with the non-zero fields of o set over it
+func (p Policy) merge(o Policy) Policy {
+	if o.Timeout > 0 {
+		p.Timeout = o.Timeout
+	}
+
+	if o.MaxAttempts > 0 {
+		p.MaxAttempts = o.MaxAttempts
+	}
+
+	if o.InitialBackoff > 0 {
+		p.InitialBackoff = o.InitialBackoff
+	}
+
+	if o.MaxBackoff > 0 {
+		p.MaxBackoff = o.MaxBackoff
+	}
+
+	if o.BackoffMultiplier > 0 {
+		p.BackoffMultiplier = o.BackoffMultiplier
+	}
+
+	if o.Jitter > 0 {
+		p.Jitter = o.Jitter
+	}
+
+	if len(o.RetryableCodes) > 0 {
+		p.RetryableCodes = o.RetryableCodes
+	}
+
+	if o.HedgingDelay > 0 {
+		p.HedgingDelay = o.HedgingDelay
+	}
+
+	if o.Idempotent {
+		p.Idempotent = true
+	}
+
+	return p
+}
+
+// attempts returns the number of attempts allowed for the call
+func (p Policy) attempts() int {
+	if !p.Idempotent || p.MaxAttempts < 1 {
+		return 1
+	}
+
+	return p.MaxAttempts
+}
+
+// retryable returns true if the failed attempt can be retried
+func (p Policy) retryable(err error) bool {
+	code := status.Code(err)
+	for _, retryable := range p.RetryableCodes {
+		if code == retryable {
+			return true
+		}
+	}
+
+	return false
+}
+
+// backoff returns the jittered time to wait after the given attempt
+func (p Policy) backoff(attempt int) time.Duration {
+	backoff := float64(p.InitialBackoff) * math.Pow(p.BackoffMultiplier, float64(attempt-1))
+	if max := float64(p.MaxBackoff); p.MaxBackoff > 0 && backoff > max {
+		backoff = max
+	}
+
+	backoff *= 1 + p.Jitter*(2*rand.Float64()-1)
+	return time.Duration(backoff)
+}
+
+// invoker makes a single attempt of the call, decoding the response in res
+type invoker func(ctx context.Context, res interface{}) error
+
+// invoke makes the call following the policy
+func (p Policy) invoke(ctx context.Context, res interface{}, call invoker) error {
+	if _, ok := ctx.Deadline(); !ok && p.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.Timeout)
+		defer cancel()
+	}
+
+	if p.HedgingDelay > 0 && p.attempts() > 1 {
+		return p.hedge(ctx, res, call)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := call(ctx, res)
+		if err == nil || attempt >= p.attempts() || !p.retryable(err) {
+			return err
+		}
+
+		timer := time.NewTimer(p.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// hedge sends another attempt after every hedging delay, or right after a retryable failure,
+// and returns the response of the first successful attempt
+func (p Policy) hedge(ctx context.Context, res interface{}, call invoker) error {
+	msg, ok := res.(proto.Message)
+	if !ok {
+		// Hedged attempts need their own response
+		return call(ctx, res)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		reply proto.Message
+		err   error
+	}
+
+	results := make(chan result, p.attempts())
+	launched := 0
+	var hedge <-chan time.Time
+	launch := func() {
+		launched++
+		reply := msg.ProtoReflect().New().Interface()
+		go func() {
+			results <- result{reply, call(ctx, reply)}
+		}()
+
+		hedge = nil
+		if launched < p.attempts() {
+			hedge = time.After(p.HedgingDelay)
+		}
+	}
+
+	launch()
+
+	var err error
+	for done := 0; done < launched; {
+		select {
+		case <-hedge:
+			launch()
+		case r := <-results:
+			done++
+			if r.err == nil {
+				proto.Reset(msg)
+				proto.Merge(msg, r.reply)
+				return nil
+			}
+
+			err = r.err
+			if !p.retryable(err) {
+				return err
+			}
+
+			if launched < p.attempts() {
+				launch()
+			}
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,180 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/easeq/go-service/pool"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func testPolicy() Policy {
+	return Policy{
+		Timeout:           time.Second,
+		MaxAttempts:       3,
+		InitialBackoff:    time.Millisecond,
+		MaxBackoff:        10 * time.Millisecond,
+		BackoffMultiplier: 2,
+		Jitter:            0.2,
+		RetryableCodes:    []codes.Code{codes.Unavailable},
+	}
+}
+
+func TestPolicyRetries(t *testing.T) {
+	tests := []struct {
+		name       string
+		idempotent bool
+		errs       []codes.Code
+		wantCalls  int32
+		wantCode   codes.Code
+	}{
+		{
+			name:       "retriedUntilSuccess",
+			idempotent: true,
+			errs:       []codes.Code{codes.Unavailable, codes.Unavailable, codes.OK},
+			wantCalls:  3,
+			wantCode:   codes.OK,
+		},
+		{
+			name:       "attemptsExhausted",
+			idempotent: true,
+			errs:       []codes.Code{codes.Unavailable, codes.Unavailable, codes.Unavailable},
+			wantCalls:  3,
+			wantCode:   codes.Unavailable,
+		},
+		{
+			name:       "notRetryable",
+			idempotent: true,
+			errs:       []codes.Code{codes.InvalidArgument, codes.OK},
+			wantCalls:  1,
+			wantCode:   codes.InvalidArgument,
+		},
+		{
+			name:      "notIdempotent",
+			errs:      []codes.Code{codes.Unavailable, codes.OK},
+			wantCalls: 1,
+			wantCode:  codes.Unavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := testPolicy()
+			p.Idempotent = tt.idempotent
+
+			var calls int32
+			err := p.invoke(context.Background(), nil, func(ctx context.Context, res interface{}) error {
+				_, ok := ctx.Deadline()
+				require.True(t, ok)
+
+				code := tt.errs[atomic.AddInt32(&calls, 1)-1]
+				return status.Error(code, code.String())
+			})
+
+			require.Equal(t, tt.wantCode, status.Code(err))
+			require.Equal(t, tt.wantCalls, calls)
+		})
+	}
+}
+
+func TestPolicyHedging(t *testing.T) {
+	p := testPolicy()
+	p.Idempotent = true
+	p.HedgingDelay = 5 * time.Millisecond
+
+	// The first attempt hangs, the hedged attempt succeeds
+	var calls int32
+	res := &grpc_health_v1.HealthCheckResponse{}
+	err := p.invoke(context.Background(), res, func(ctx context.Context, res interface{}) error {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			<-ctx.Done()
+			return status.FromContextError(ctx.Err()).Err()
+		}
+
+		proto.Merge(res.(proto.Message), &grpc_health_v1.HealthCheckResponse{
+			Status: grpc_health_v1.HealthCheckResponse_SERVING,
+		})
+		return nil
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, res.Status)
+	require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestPolicyBackoff(t *testing.T) {
+	p := testPolicy()
+	for attempt, want := range []time.Duration{time.Millisecond, 2 * time.Millisecond, 4 * time.Millisecond} {
+		backoff := p.backoff(attempt + 1)
+		require.InDelta(t, float64(want), float64(backoff), float64(want)*p.Jitter)
+	}
+
+	require.LessOrEqual(t, p.backoff(10), time.Duration(float64(p.MaxBackoff)*(1+p.Jitter)))
+}
+
+func TestBreaker(t *testing.T) {
+	b := newBreaker(2, 20*time.Millisecond)
+	unavailable := status.Error(codes.Unavailable, "unavailable")
+
+	allowed, probe := b.allow()
+	require.True(t, allowed)
+	require.False(t, probe)
+	b.record(unavailable, probe)
+	allowed, _ = b.allow()
+	require.True(t, allowed)
+	b.record(unavailable, false)
+
+	// Open, the calls fail fast
+	allowed, _ = b.allow()
+	require.False(t, allowed)
+
+	// Half open, a single call is let through
+	time.Sleep(30 * time.Millisecond)
+	allowed, probe = b.allow()
+	require.True(t, allowed)
+	require.True(t, probe)
+	allowed, _ = b.allow()
+	require.False(t, allowed)
+
+	// A call made before the breaker opened doesn't let another call through
+	b.record(unavailable, false)
+	allowed, _ = b.allow()
+	require.False(t, allowed)
+
+	b.record(nil, true)
+	allowed, probe = b.allow()
+	require.True(t, allowed)
+	require.False(t, probe)
+}
+
+func TestBreakerFailures(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		failure bool
+	}{
+		{"Success", nil, false},
+		{"Unavailable", status.Error(codes.Unavailable, "unavailable"), true},
+		{"NotFound", status.Error(codes.NotFound, "not found"), false},
+		{"ConnectionError", errors.New("connection refused"), true},
+		{"PoolExhausted", pool.ErrPoolExhausted, true},
+		{"Cancelled", context.Canceled, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newBreaker(1, time.Minute)
+			b.record(tt.err, false)
+
+			allowed, _ := b.allow()
+			require.Equal(t, !tt.failure, allowed)
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/easeq/go-service/logger"
+	"github.com/easeq/go-service/registry"
+	"google.golang.org/grpc/resolver"
+)
+
+const (
+	// SCHEME is the target scheme resolved using the service registry
+	SCHEME = "registry"
+	// RESOLVER_RETRY_BACKOFF is the time to wait before watching the registry again after an error
+	RESOLVER_RETRY_BACKOFF = time.Second
+)
+
+var (
+	// ErrRegistryNotDefined returned when resolving a service without a registry
+	ErrRegistryNotDefined = errors.New("service registry not defined")
+)
+
+// resolverBuilder builds the resolvers of the registry scheme
+type resolverBuilder struct {
+	registry registry.ServiceRegistry
+	logger   logger.Logger
+}
+
+// NewResolverBuilder returns a gRPC resolver builder that resolves
+// the service names to the healthy instances in the service registry
+func NewResolverBuilder(r registry.ServiceRegistry, l logger.Logger) resolver.Builder {
+	return &resolverBuilder{r, l}
+}
+
+// Build starts watching the service registry for the instances of the target service
+func (b *resolverBuilder) Build(
+	target resolver.Target,
+	cc resolver.ClientConn,
+	opts resolver.BuildOptions,
+) (resolver.Resolver, error) {
+	if b.registry == nil {
+		return nil, ErrRegistryNotDefined
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &registryResolver{
+		name:     strings.TrimPrefix(target.URL.Path, "/"),
+		registry: b.registry,
+		logger:   b.logger,
+		cc:       cc,
+		cancel:   cancel,
+	}
+
+	r.wg.Add(1)
+	go r.watch(ctx)
+
+	return r, nil
+}
+
+// Scheme returns the scheme resolved by the builder
+func (b *resolverBuilder) Scheme() string {
+	return SCHEME
+}
+
+// registryResolver updates the client connection with the instances of the service
+type registryResolver struct {
+	name     string
+	registry registry.ServiceRegistry
+	logger   logger.Logger
+	cc       resolver.ClientConn
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// watch updates the client connection every time the service instances change
+func (r *registryResolver) watch(ctx context.Context) {
+	defer r.wg.Done()
+
+	for {
+		err := r.update(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		r.logger.Errorw("resolver watch error", "service", r.name, "error", err)
+		r.cc.ReportError(err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(RESOLVER_RETRY_BACKOFF):
+		}
+	}
+}
+
+// update watches the registry and updates the client connection until an error occurs
+func (r *registryResolver) update(ctx context.Context) error {
+	w, err := r.registry.Watch(ctx, r.name)
+	if err != nil {
+		return err
+	}
+
+	defer w.Stop()
+
+	for {
+		instances, err := w.Next()
+		if err != nil {
+			return err
+		}
+
+		instances = registry.Healthy(instances)
+		addresses := make([]resolver.Address, len(instances))
+		for i, instance := range instances {
+			addresses[i] = resolver.Address{
+				Addr:       net.JoinHostPort(instance.Address, strconv.Itoa(instance.Port)),
+				ServerName: r.name,
+			}
+		}
+
+		if err := r.cc.UpdateState(resolver.State{Addresses: addresses}); err != nil {
+			r.logger.Debugw("resolver update error", "service", r.name, "error", err)
+		}
+	}
+}
+
+// ResolveNow is a no-op, the instances are watched continuously
+func (r *registryResolver) ResolveNow(resolver.ResolveNowOptions) {}
+
+// Close stops watching the service registry
+func (r *registryResolver) Close() {
+	r.cancel()
+	r.wg.Wait()
+}
@@ -0,0 +1,115 @@
+package grpc
+
+import (
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/easeq/go-service/pool"
+	"google.golang.org/grpc"
+)
+
+// GrpcStreamClient is the gRPC client that allows streaming. It holds the stream and the connection to the gRPC server.
+// The connection is released when the stream terminates, fails or its context is cancelled.
+type GrpcStreamClient struct {
+	stream grpc.ClientStream
+	desc   *grpc.StreamDesc
+	conn   pool.Connection
+	once   sync.Once
+	done   chan struct{}
+}
+
+// newStreamClient returns the stream client releasing the connection once the stream is done
+func newStreamClient(stream grpc.ClientStream, desc *grpc.StreamDesc, conn pool.Connection) *GrpcStreamClient {
+	sc := &GrpcStreamClient{
+		stream: stream,
+		desc:   desc,
+		conn:   conn,
+		done:   make(chan struct{}),
+	}
+
+	// The stream context is cancelled when the stream terminates or the call context is done
+	go func() {
+		select {
+		case <-stream.Context().Done():
+			sc.CloseConn()
+		case <-sc.done:
+		}
+	}()
+
+	return sc
+}
+
+// Recv receive a message from the stream.
+// The connection is released when the stream ends with io.EOF or an error.
+func (sc *GrpcStreamClient) Recv(res interface{}) error {
+	if err := sc.stream.RecvMsg(res); err != nil {
+		sc.CloseConn()
+		return err
+	}
+
+	return nil
+}
+
+// Send sends a message.
+// On io.EOF the stream has terminated and the status is returned by Recv.
+func (sc *GrpcStreamClient) Send(req interface{}) error {
+	err := sc.stream.SendMsg(req)
+	if err != nil && !errors.Is(err, io.EOF) {
+		sc.CloseConn()
+	}
+
+	return err
+}
+
+// CloseSend closes the sending side of the stream, the messages can still be received
+func (sc *GrpcStreamClient) CloseSend() error {
+	return sc.stream.CloseSend()
+}
+
+// CloseAndRecv first close the server stream and receives messages on the client stream
+func (sc *GrpcStreamClient) CloseAndRecv(res interface{}) error {
+	if err := sc.stream.CloseSend(); err != nil {
+		sc.CloseConn()
+		return err
+	}
+
+	if err := sc.Recv(res); err != nil {
+		return err
+	}
+
+	// The client streams terminate with the only response
+	if !sc.desc.ServerStreams {
+		sc.CloseConn()
+	}
+
+	return nil
+}
+
+// Header returns the header metadata received from the server
+func (sc *GrpcStreamClient) Header() (map[string][]string, error) {
+	md, err := sc.stream.Header()
+	if err != nil {
+		sc.CloseConn()
+		return nil, err
+	}
+
+	return md, nil
+}
+
+// Trailer returns the trailer metadata, available once Recv returns an error
+func (sc *GrpcStreamClient) Trailer() map[string][]string {
+	return sc.stream.Trailer()
+}
+
+// CloseConn releases the connection back to the pool, or closes it if the pool is full.
+// It is called when the stream terminates, calling it again is a no-op.
+func (sc *GrpcStreamClient) CloseConn() error {
+	var err error
+	sc.once.Do(func() {
+		close(sc.done)
+		err = sc.conn.Close()
+	})
+
+	return err
+}
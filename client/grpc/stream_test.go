@@ -0,0 +1,112 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/easeq/go-service/pool"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+var watchDesc = &grpc.StreamDesc{StreamName: "Watch", ServerStreams: true}
+
+// newPooledClient returns a client dialing the test server through the pool
+func newPooledClient(t *testing.T) *Grpc {
+	var calls int64
+	instance := startServer(t, &calls)
+
+	c := NewGrpc(
+		WithRegistry(&staticRegistry{}),
+		WithConnFactory(func(address string) (*grpc.ClientConn, error) {
+			return grpc.Dial(net.JoinHostPort(instance.Address, strconv.Itoa(instance.Port)), grpc.WithInsecure())
+		}),
+	)
+	c.logger = nopLogger{}
+	t.Cleanup(func() { c.Close() })
+
+	return c
+}
+
+func inUse(c *Grpc) int {
+	return c.pool.Stats()[""].InUse
+}
+
+func TestStreamReleasedOnCancel(t *testing.T) {
+	c := newPooledClient(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := c.Stream(ctx, testServiceClient{}, watchDesc, "/grpc.health.v1.Health/Watch", &grpc_health_v1.HealthCheckRequest{})
+	require.NoError(t, err)
+
+	res := &grpc_health_v1.HealthCheckResponse{}
+	require.NoError(t, stream.Recv(res))
+	require.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, res.Status)
+
+	_, err = stream.Header()
+	require.NoError(t, err)
+	require.Equal(t, 1, inUse(c))
+
+	// The connection is released without CloseConn
+	cancel()
+	require.Eventually(t, func() bool {
+		return inUse(c) == 0
+	}, time.Second, time.Millisecond)
+	require.NoError(t, stream.CloseConn())
+}
+
+func TestStreamReleasedOnError(t *testing.T) {
+	c := newPooledClient(t)
+
+	stream, err := c.Stream(
+		context.Background(),
+		testServiceClient{},
+		watchDesc,
+		"/grpc.health.v1.Health/Unknown",
+		&grpc_health_v1.HealthCheckRequest{},
+	)
+	require.NoError(t, err)
+
+	err = stream.Recv(&grpc_health_v1.HealthCheckResponse{})
+	require.Equal(t, codes.Unimplemented, status.Code(err))
+	require.Equal(t, pool.Stats{Idle: 1, Created: 1}, c.pool.Stats()[""])
+	require.NotNil(t, stream.Trailer())
+}
+
+func TestStreamCloseConnOnce(t *testing.T) {
+	var closed int64
+	sc := newStreamClient(nopStream{ctx: context.Background()}, watchDesc, closeCounter{&closed})
+
+	require.NoError(t, sc.CloseConn())
+	require.NoError(t, sc.CloseConn())
+	require.Equal(t, int64(1), atomic.LoadInt64(&closed))
+}
+
+type nopStream struct {
+	grpc.ClientStream
+	ctx context.Context
+}
+
+func (s nopStream) Context() context.Context { return s.ctx }
+
+type closeCounter struct {
+	closed *int64
+}
+
+func (c closeCounter) Address() string { return "" }
+
+func (c closeCounter) Conn() pool.FactoryConn { return nil }
+
+func (c closeCounter) Close() error {
+	atomic.AddInt64(c.closed, 1)
+	return nil
+}
@@ -0,0 +1,70 @@
+package client
+
+import "time"
+
+// CallOptions holds the options of a single call
+type CallOptions struct {
+	// Idempotent marks the call safe to retry or hedge
+	Idempotent bool
+	// Timeout is the deadline of the call, if the context has none. 0 leaves it to the client.
+	Timeout time.Duration
+	// MaxAttempts is the maximum number of attempts of the call. 0 leaves it to the client.
+	MaxAttempts int
+	// RetryableCodes are the names of the status codes retried, e.g. "UNAVAILABLE"
+	RetryableCodes []string
+	// Options holds the options specific to the client implementation,
+	// e.g. grpc.CallOption for the gRPC client
+	Options []interface{}
+}
+
+// CallOption is used to pass client call options
+type CallOption func(*CallOptions)
+
+// NewCallOptions returns the call options after applying the options passed
+func NewCallOptions(opts ...CallOption) *CallOptions {
+	o := &CallOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// Idempotent marks the call as idempotent, i.e. safe to retry or hedge.
+// The generated clients pass it for the methods with an idempotency_level option or an idempotent method_policy.
+func Idempotent() CallOption {
+	return func(o *CallOptions) {
+		o.Idempotent = true
+	}
+}
+
+// WithTimeout sets the deadline of the call, if the context has none
+func WithTimeout(timeout time.Duration) CallOption {
+	return func(o *CallOptions) {
+		o.Timeout = timeout
+	}
+}
+
+// WithMaxAttempts sets the maximum number of attempts of the call
+func WithMaxAttempts(n int) CallOption {
+	return func(o *CallOptions) {
+		o.MaxAttempts = n
+	}
+}
+
+// WithRetryableCodes sets the names of the status codes retried, e.g. "UNAVAILABLE".
+// The generated clients pass it for the methods with a retryable_codes option.
+// The client returns an error for the invalid names.
+func WithRetryableCodes(names ...string) CallOption {
+	return func(o *CallOptions) {
+		o.RetryableCodes = names
+	}
+}
+
+// WithOption passes an option specific to the client implementation.
+// The client returns an error for the options it doesn't support.
+func WithOption(opt interface{}) CallOption {
+	return func(o *CallOptions) {
+		o.Options = append(o.Options, opt)
+	}
+}
@@ -0,0 +1,219 @@
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"time"
+
+	grpc_error "github.com/easeq/go-service/server/grpc/error"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// INTERNAL_MESSAGE is the message returned to the clients in place of the internal errors
+	INTERNAL_MESSAGE = "internal error"
+)
+
+var (
+	// ErrValidation matches the errors of invalid requests
+	ErrValidation = New(codes.InvalidArgument, "invalid request")
+	// ErrNotFound matches the errors of resources not found
+	ErrNotFound = New(codes.NotFound, "not found")
+	// ErrConflict matches the errors of resources already existing
+	ErrConflict = New(codes.AlreadyExists, "already exists")
+	// ErrRateLimited matches the errors of requests exceeding the rate limits
+	ErrRateLimited = New(codes.ResourceExhausted, "rate limited")
+	// ErrUnauthenticated matches the errors of requests without valid credentials
+	ErrUnauthenticated = New(codes.Unauthenticated, "unauthenticated")
+	// ErrPermissionDenied matches the errors of requests not allowed for the caller
+	ErrPermissionDenied = New(codes.PermissionDenied, "permission denied")
+	// ErrInternal matches the internal errors
+	ErrInternal = New(codes.Internal, INTERNAL_MESSAGE)
+)
+
+// FieldViolation describes an invalid field of the request
+type FieldViolation struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+// Error is a domain error, mapped to gRPC statuses and HTTP responses.
+// The message, violations and retry delay are returned to the clients,
+// the wrapped error is only available to the service.
+type Error struct {
+	Code       codes.Code
+	Message    string
+	Violations []FieldViolation
+	RetryAfter time.Duration
+	err        error
+}
+
+// New returns a new error with the code and the message returned to the clients
+func New(code codes.Code, msg string) *Error {
+	return &Error{Code: code, Message: msg}
+}
+
+// Wrap returns a new error with the code and message, wrapping err
+func Wrap(err error, code codes.Code, msg string) *Error {
+	return &Error{Code: code, Message: msg, err: err}
+}
+
+// Validation returns an invalid argument error with the field violations
+func Validation(msg string, violations ...FieldViolation) *Error {
+	return &Error{Code: codes.InvalidArgument, Message: msg, Violations: violations}
+}
+
+// Violation returns the violation of the field
+func Violation(field string, description string) FieldViolation {
+	return FieldViolation{Field: field, Description: description}
+}
+
+// NotFound returns a not found error
+func NotFound(msg string) *Error {
+	return New(codes.NotFound, msg)
+}
+
+// Conflict returns an already exists error
+func Conflict(msg string) *Error {
+	return New(codes.AlreadyExists, msg)
+}
+
+// RateLimited returns a resource exhausted error, retryable after the delay
+func RateLimited(msg string, retryAfter time.Duration) *Error {
+	return &Error{Code: codes.ResourceExhausted, Message: msg, RetryAfter: retryAfter}
+}
+
+// Unauthenticated returns an unauthenticated error
+func Unauthenticated(msg string) *Error {
+	return New(codes.Unauthenticated, msg)
+}
+
+// PermissionDenied returns a permission denied error
+func PermissionDenied(msg string) *Error {
+	return New(codes.PermissionDenied, msg)
+}
+
+// Internal returns an internal error wrapping err, its details aren't returned to the clients
+func Internal(err error) *Error {
+	return Wrap(err, codes.Internal, INTERNAL_MESSAGE)
+}
+
+// Error returns the message and the wrapped error
+func (e *Error) Error() string {
+	if e.err == nil {
+		return e.Message
+	}
+
+	return fmt.Sprintf("%s: %v", e.Message, e.err)
+}
+
+// Unwrap returns the wrapped error
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// Is reports whether the target is an error with the same code
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Detail returns the error detail sent to the clients
+func (e *Error) Detail() *grpc_error.ErrorDetail {
+	detail := &grpc_error.ErrorDetail{
+		Code:         int32(e.Code),
+		Status:       e.Code.String(),
+		Message:      e.Message,
+		Public:       true,
+		RetryAfterMs: e.RetryAfter.Milliseconds(),
+	}
+
+	for _, v := range e.Violations {
+		detail.Violations = append(detail.Violations, &grpc_error.FieldViolation{
+			Field:       v.Field,
+			Description: v.Description,
+		})
+	}
+
+	return detail
+}
+
+// GRPCStatus returns the gRPC status of the error, used by the gRPC server
+func (e *Error) GRPCStatus() *status.Status {
+	st := status.New(e.Code, e.Message)
+	if detailed, err := st.WithDetails(e.Detail()); err == nil {
+		return detailed
+	}
+
+	return st
+}
+
+// FromError returns the domain error of err, using the default mappings.
+// The gRPC statuses carrying a public error detail are decoded,
+// the messages of the unknown and internal errors are replaced by INTERNAL_MESSAGE.
+func FromError(err error) *Error {
+	return fromError(err, nil)
+}
+
+// fromError returns the domain error of err, using the mappers before the default mappings
+func fromError(err error, mappers []Mapper) *Error {
+	if err == nil {
+		return nil
+	}
+
+	var e *Error
+	if stderrors.As(err, &e) {
+		return e
+	}
+
+	for _, mapper := range mappers {
+		if e := mapper(err); e != nil {
+			return e
+		}
+	}
+
+	for _, mapper := range defaultMappers {
+		if e := mapper(err); e != nil {
+			return e
+		}
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return Internal(err)
+	}
+
+	for _, detail := range st.Details() {
+		if d, ok := detail.(*grpc_error.ErrorDetail); ok && d.Public {
+			return fromDetail(err, st.Code(), d)
+		}
+	}
+
+	switch st.Code() {
+	case codes.Unknown, codes.Internal, codes.DataLoss:
+		return Wrap(err, st.Code(), INTERNAL_MESSAGE)
+	}
+
+	return Wrap(err, st.Code(), st.Message())
+}
+
+// fromDetail returns the domain error of the status error with the public error detail
+func fromDetail(err error, code codes.Code, d *grpc_error.ErrorDetail) *Error {
+	e := Wrap(err, code, d.Message)
+	e.RetryAfter = time.Duration(d.RetryAfterMs) * time.Millisecond
+	for _, v := range d.Violations {
+		e.Violations = append(e.Violations, Violation(v.Field, v.Description))
+	}
+
+	return e
+}
+
+// Code returns the gRPC code of the error
+func Code(err error) codes.Code {
+	if err == nil {
+		return codes.OK
+	}
+
+	return FromError(err).Code
+}
@@ -0,0 +1,154 @@
+package errors
+
+import (
+	"context"
+	"database/sql"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	grpc_error "github.com/easeq/go-service/server/grpc/error"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFromError(t *testing.T) {
+	cause := stderrors.New("connection refused: db-1.internal:5432")
+	tests := []struct {
+		name    string
+		err     error
+		code    codes.Code
+		message string
+	}{
+		{
+			name:    "domain error",
+			err:     fmt.Errorf("loading book: %w", NotFound("book not found")),
+			code:    codes.NotFound,
+			message: "book not found",
+		},
+		{
+			name:    "internal error",
+			err:     Internal(cause),
+			code:    codes.Internal,
+			message: INTERNAL_MESSAGE,
+		},
+		{
+			name:    "plain error",
+			err:     cause,
+			code:    codes.Internal,
+			message: INTERNAL_MESSAGE,
+		},
+		{
+			name:    "no rows",
+			err:     fmt.Errorf("query: %w", sql.ErrNoRows),
+			code:    codes.NotFound,
+			message: ErrNotFound.Message,
+		},
+		{
+			name:    "deadline exceeded",
+			err:     context.DeadlineExceeded,
+			code:    codes.DeadlineExceeded,
+			message: "deadline exceeded",
+		},
+		{
+			name:    "status error",
+			err:     status.Error(codes.FailedPrecondition, "shelf is full"),
+			code:    codes.FailedPrecondition,
+			message: "shelf is full",
+		},
+		{
+			name:    "internal status error",
+			err:     status.Error(codes.Unknown, cause.Error()),
+			code:    codes.Unknown,
+			message: INTERNAL_MESSAGE,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := FromError(tt.err)
+			require.Equal(t, tt.code, e.Code)
+			require.Equal(t, tt.message, e.Message)
+			require.Equal(t, tt.code, Code(tt.err))
+		})
+	}
+
+	require.Nil(t, FromError(nil))
+	require.Equal(t, codes.OK, Code(nil))
+}
+
+func TestGRPCStatus(t *testing.T) {
+	err := Validation("invalid book", Violation("book.title", "must not be empty"))
+	require.ErrorIs(t, err, ErrValidation)
+	require.NotErrorIs(t, err, ErrNotFound)
+
+	st := status.Convert(err)
+	require.Equal(t, codes.InvalidArgument, st.Code())
+	require.Equal(t, "invalid book", st.Message())
+	require.Len(t, st.Details(), 1)
+
+	detail := st.Details()[0].(*grpc_error.ErrorDetail)
+	require.True(t, detail.Public)
+	require.Equal(t, "book.title", detail.Violations[0].Field)
+
+	// The error is decoded back from the status received by the clients
+	e := FromError(st.Err())
+	require.Equal(t, codes.InvalidArgument, e.Code)
+	require.Equal(t, []FieldViolation{Violation("book.title", "must not be empty")}, e.Violations)
+
+	limited := FromError(status.Convert(RateLimited("slow down", 1500*time.Millisecond)).Err())
+	require.ErrorIs(t, limited, ErrRateLimited)
+	require.Equal(t, 1500*time.Millisecond, limited.RetryAfter)
+	require.Equal(t, "2", limited.RetryAfterHeader())
+}
+
+func TestWrap(t *testing.T) {
+	cause := stderrors.New("duplicate key value violates unique constraint")
+	err := Wrap(cause, codes.AlreadyExists, "book already exists")
+
+	require.ErrorIs(t, err, cause)
+	require.ErrorIs(t, err, ErrConflict)
+	require.Contains(t, err.Error(), cause.Error())
+
+	// The wrapped error isn't sent to the clients
+	require.NotContains(t, status.Convert(err).Message(), cause.Error())
+	require.Equal(t, "book already exists", err.Response().Message)
+}
+
+func TestWriteHTTP(t *testing.T) {
+	rec := httptest.NewRecorder()
+	WriteHTTP(rec, RateLimited("slow down", time.Second), 0)
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+	require.Equal(t, "1", rec.Header().Get(HEADER_RETRY_AFTER))
+	require.JSONEq(t, `{"code":8,"status":"ResourceExhausted","message":"slow down","retryAfterMs":1000}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	WriteHTTP(rec, stderrors.New("secret"), 0)
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+	require.NotContains(t, rec.Body.String(), "secret")
+
+	rec = httptest.NewRecorder()
+	WriteHTTP(rec, NotFound("no route"), http.StatusMethodNotAllowed)
+	require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestCodeFromHTTPStatus(t *testing.T) {
+	for _, code := range []codes.Code{
+		codes.InvalidArgument,
+		codes.Unauthenticated,
+		codes.PermissionDenied,
+		codes.NotFound,
+		codes.AlreadyExists,
+		codes.ResourceExhausted,
+		codes.Unimplemented,
+		codes.Unavailable,
+		codes.DeadlineExceeded,
+		codes.Internal,
+	} {
+		require.Equal(t, code, CodeFromHTTPStatus(New(code, "").HTTPStatus()), code.String())
+	}
+}
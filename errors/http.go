@@ -0,0 +1,102 @@
+package errors
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	// HEADER_RETRY_AFTER is the header holding the seconds after which a rate limited request can be retried
+	HEADER_RETRY_AFTER = "Retry-After"
+)
+
+// Response is the JSON body of the HTTP error responses
+type Response struct {
+	Code         int32            `json:"code"`
+	Status       string           `json:"status"`
+	Message      string           `json:"message"`
+	Violations   []FieldViolation `json:"violations,omitempty"`
+	RetryAfterMs int64            `json:"retryAfterMs,omitempty"`
+}
+
+// Response returns the HTTP response body of the error
+func (e *Error) Response() *Response {
+	return &Response{
+		Code:         int32(e.Code),
+		Status:       e.Code.String(),
+		Message:      e.Message,
+		Violations:   e.Violations,
+		RetryAfterMs: e.RetryAfter.Milliseconds(),
+	}
+}
+
+// HTTPStatus returns the HTTP status code of the error
+func (e *Error) HTTPStatus() int {
+	return runtime.HTTPStatusFromCode(e.Code)
+}
+
+// RetryAfterHeader returns the Retry-After header value of the error, in whole seconds,
+// or an empty string if the error isn't retryable after a delay
+func (e *Error) RetryAfterHeader() string {
+	if e.RetryAfter <= 0 {
+		return ""
+	}
+
+	return strconv.Itoa(int(math.Ceil(e.RetryAfter.Seconds())))
+}
+
+// CodeFromHTTPStatus returns the gRPC code of the HTTP status code
+func CodeFromHTTPStatus(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusOK:
+		return codes.OK
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusPreconditionFailed:
+		return codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusNotImplemented, http.StatusMethodNotAllowed:
+		return codes.Unimplemented
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout, http.StatusRequestTimeout:
+		return codes.DeadlineExceeded
+	}
+
+	if httpStatus >= http.StatusInternalServerError {
+		return codes.Internal
+	}
+
+	return codes.Unknown
+}
+
+// WriteHTTP writes the JSON response of the error, with the HTTP status code
+// of the error unless a non zero httpStatus is given
+func WriteHTTP(w http.ResponseWriter, err error, httpStatus int) {
+	e := FromError(err)
+	if httpStatus == 0 {
+		httpStatus = e.HTTPStatus()
+	}
+
+	if retryAfter := e.RetryAfterHeader(); retryAfter != "" {
+		w.Header().Set(HEADER_RETRY_AFTER, retryAfter)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus)
+	json.NewEncoder(w).Encode(e.Response())
+}
@@ -0,0 +1,101 @@
+package errors
+
+import (
+	"context"
+	"database/sql"
+	stderrors "errors"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+)
+
+// Mapper returns the domain error of err, or nil if it doesn't map err
+type Mapper func(err error) *Error
+
+// defaultMappers map the errors of the standard library and the validation errors
+var defaultMappers = []Mapper{
+	Map(context.DeadlineExceeded, codes.DeadlineExceeded, "deadline exceeded"),
+	Map(context.Canceled, codes.Canceled, "canceled"),
+	Map(sql.ErrNoRows, codes.NotFound, ErrNotFound.Message),
+	mapValidation,
+}
+
+// fieldError is a validation error of a single field, e.g. the errors generated by protoc-gen-validate
+type fieldError interface {
+	Field() string
+	Reason() string
+}
+
+// multiError holds multiple errors, e.g. the errors returned by the ValidateAll methods
+// generated by protoc-gen-validate
+type multiError interface {
+	AllErrors() []error
+}
+
+// Map returns the mapper of the errors matching target to errors with the code and message
+func Map(target error, code codes.Code, msg string) Mapper {
+	return func(err error) *Error {
+		if !stderrors.Is(err, target) {
+			return nil
+		}
+
+		return Wrap(err, code, msg)
+	}
+}
+
+// mapValidation maps the field validation errors to invalid argument errors with the field violations
+func mapValidation(err error) *Error {
+	var errs []error
+	var multi multiError
+	if stderrors.As(err, &multi) {
+		errs = multi.AllErrors()
+	} else {
+		errs = []error{err}
+	}
+
+	var violations []FieldViolation
+	for _, err := range errs {
+		var field fieldError
+		if !stderrors.As(err, &field) {
+			return nil
+		}
+
+		violations = append(violations, Violation(field.Field(), field.Reason()))
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	e := Validation(ErrValidation.Message, violations...)
+	e.err = err
+	return e
+}
+
+// Registry maps the errors to domain errors, using the mappers registered before the default mappings
+type Registry struct {
+	mu      sync.RWMutex
+	mappers []Mapper
+}
+
+// NewRegistry returns a new registry with the mappers
+func NewRegistry(mappers ...Mapper) *Registry {
+	return &Registry{mappers: mappers}
+}
+
+// Register adds the mappers to the registry, after the ones already registered
+func (r *Registry) Register(mappers ...Mapper) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.mappers = append(r.mappers, mappers...)
+}
+
+// FromError returns the domain error of err, mapped by the first matching mapper
+func (r *Registry) FromError(err error) *Error {
+	r.mu.RLock()
+	mappers := r.mappers
+	r.mu.RUnlock()
+
+	return fromError(err, mappers)
+}
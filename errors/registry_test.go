@@ -0,0 +1,59 @@
+package errors
+
+import (
+	"database/sql"
+	stderrors "errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+// fieldErr is a field validation error, like the ones generated by protoc-gen-validate
+type fieldErr struct {
+	field  string
+	reason string
+}
+
+func (e fieldErr) Field() string  { return e.field }
+func (e fieldErr) Reason() string { return e.reason }
+func (e fieldErr) Error() string  { return e.field + ": " + e.reason }
+
+// multiErr holds multiple field validation errors
+type multiErr []error
+
+func (m multiErr) AllErrors() []error { return m }
+func (m multiErr) Error() string      { return fmt.Sprint([]error(m)) }
+
+func TestRegistry(t *testing.T) {
+	errShelfFull := stderrors.New("shelf is full")
+	r := NewRegistry(Map(errShelfFull, codes.FailedPrecondition, "shelf is full"))
+
+	e := r.FromError(fmt.Errorf("adding book: %w", errShelfFull))
+	require.Equal(t, codes.FailedPrecondition, e.Code)
+	require.ErrorIs(t, e, errShelfFull)
+
+	// The default mappings apply after the registered ones
+	require.Equal(t, codes.NotFound, r.FromError(sql.ErrNoRows).Code)
+	require.Equal(t, codes.Internal, FromError(errShelfFull).Code)
+
+	r.Register(func(err error) *Error {
+		return Wrap(err, codes.Unavailable, "unavailable")
+	})
+	require.Equal(t, codes.Unavailable, r.FromError(stderrors.New("other")).Code)
+}
+
+func TestValidationMapping(t *testing.T) {
+	e := FromError(fieldErr{"title", "must not be empty"})
+	require.ErrorIs(t, e, ErrValidation)
+	require.Equal(t, []FieldViolation{Violation("title", "must not be empty")}, e.Violations)
+
+	e = FromError(multiErr{fieldErr{"title", "must not be empty"}, fieldErr{"pages", "must be positive"}})
+	require.Equal(t, codes.InvalidArgument, e.Code)
+	require.Len(t, e.Violations, 2)
+
+	// The multiple errors aren't all validation errors
+	e = FromError(multiErr{fieldErr{"title", "must not be empty"}, stderrors.New("other")})
+	require.Equal(t, codes.Internal, e.Code)
+}
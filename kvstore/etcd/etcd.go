@@ -16,7 +16,7 @@ var (
 	// ErrInvalidLeaseID returned when the leaseID provided is invalid
 	ErrInvalidLeaseID = errors.New("invalid etcd leaseID passed")
 	// ErrNoResults returned when no results are found
-	ErrNoResults = errors.New("no results found for the given key")
+	ErrNoResults = kvstore.ErrNoResults
 	// ErrCreatingEtcdClient returned when creating etcd clientv3 fails
 	ErrCreatingEtcdClient = errors.New("error creating kvstore etcd client")
 	// ErrInvalidWatchOption returned when the watch option sent to the
@@ -139,10 +139,22 @@ func (e *Etcd) RenewLease(ctx context.Context, leaseID clientv3.LeaseID) error {
 	return nil
 }
 
+// revokeNewLease revokes the lease created by LeaseID(...) for a record that wasn't saved.
+// The lease set in the record metadata is left as is.
+func (e *Etcd) revokeNewLease(ctx context.Context, record *kvstore.Record, leaseID clientv3.LeaseID) {
+	if existing, _ := e.GetMetadataLeaseID(record); leaseID == 0 || leaseID == existing {
+		return
+	}
+
+	if _, err := e.Client.Lease.Revoke(ctx, leaseID); err != nil {
+		e.logger.Errorw("Error revoking unused lease", "lease_id", leaseID, "error", err)
+	}
+}
+
 // Put adds the record into the store
 // Get the lease if lease_id is defined in the record metadata, or create new lease if expiry is defined
 // Renew lease using the lease_id in the record metadata, added/used by LeaseID(...)
-// Add the record to the store with the lease_id
+// Add the record to the store with the lease_id, only if the key doesn't exist when kvstore.IfAbsent is set
 func (e *Etcd) Put(ctx context.Context, record *kvstore.Record, opts ...kvstore.SetOpt) (*kvstore.Record, error) {
 	cb := func(ctx context.Context, record *kvstore.Record, opts ...kvstore.SetOpt) (*kvstore.Record, error) {
 		leaseID, err := e.LeaseID(ctx, record)
@@ -156,7 +168,22 @@ func (e *Etcd) Put(ctx context.Context, record *kvstore.Record, opts ...kvstore.
 			putOpts = append(putOpts, clientv3.WithLease(leaseID))
 		}
 
-		if _, err := e.Client.Put(
+		if kvstore.HasIfAbsent(opts...) {
+			// Put the record only if the key has never been created or has been deleted
+			response, err := e.Client.Txn(ctx).
+				If(clientv3.Compare(clientv3.CreateRevision(record.Key), "=", 0)).
+				Then(clientv3.OpPut(record.Key, string(record.Value), putOpts...)).
+				Commit()
+			if err != nil {
+				e.revokeNewLease(ctx, record, leaseID)
+				return nil, fmt.Errorf("Error saving record: %v", err)
+			}
+
+			if !response.Succeeded {
+				e.revokeNewLease(ctx, record, leaseID)
+				return nil, kvstore.ErrKeyExists
+			}
+		} else if _, err := e.Client.Put(
 			ctx,
 			record.Key,
 			string(record.Value),
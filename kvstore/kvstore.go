@@ -2,6 +2,7 @@ package kvstore
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/easeq/go-service/component"
@@ -11,12 +12,39 @@ const (
 	KV_STORE = "kv-store"
 )
 
+var (
+	// ErrNoResults returned when no records are found for the given key
+	ErrNoResults = errors.New("no results found for the given key")
+	// ErrKeyExists returned when a record is put with IfAbsent and the key already exists
+	ErrKeyExists = errors.New("key already exists")
+)
+
 // Option for initialization of the store
 type Option interface{}
 
 // SetOpts are the additional options passed during the set operation
 type SetOpt interface{}
 
+// ifAbsent is the SetOpt returned by IfAbsent
+type ifAbsent struct{}
+
+// IfAbsent returns the SetOpt that saves the record only if the key doesn't exist.
+// The check and the put are atomic, ErrKeyExists is returned if the key exists.
+func IfAbsent() SetOpt {
+	return ifAbsent{}
+}
+
+// HasIfAbsent returns true if the set options contain IfAbsent
+func HasIfAbsent(opts ...SetOpt) bool {
+	for _, opt := range opts {
+		if _, ok := opt.(ifAbsent); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
 // GetOpts are the additional options passed during the get opertation
 type GetOpt interface{}
 
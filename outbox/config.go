@@ -0,0 +1,51 @@
+package outbox
+
+import (
+	"time"
+
+	"github.com/Netflix/go-env"
+	"github.com/easeq/go-service/component"
+)
+
+// Config holds the outbox relay configuration
+type Config struct {
+	// PollInterval is the time between two reads of the outbox table
+	PollInterval time.Duration `env:"OUTBOX_POLL_INTERVAL,default=1s"`
+	// BatchSize is the maximum number of messages published on every poll
+	BatchSize int `env:"OUTBOX_BATCH_SIZE,default=100"`
+	// MaxAttempts is the number of publish attempts after which a message is marked as failed
+	MaxAttempts int `env:"OUTBOX_MAX_ATTEMPTS,default=10"`
+	// RetryBackoff is the initial delay before retrying a failed message, doubled on every attempt
+	RetryBackoff time.Duration `env:"OUTBOX_RETRY_BACKOFF,default=1s"`
+	// MaxRetryBackoff caps the delay between two attempts
+	MaxRetryBackoff time.Duration `env:"OUTBOX_MAX_RETRY_BACKOFF,default=5m"`
+	// LockID is the postgres advisory lock key held by the active relay
+	LockID int64 `env:"OUTBOX_LOCK_ID,default=72295730"`
+}
+
+// NewConfig returns the parsed config for the outbox from env
+func NewConfig() *Config {
+	c := new(Config)
+	component.NewConfig(c)
+
+	return c
+}
+
+// UnmarshalEnv env.EnvSet to Config
+func (c *Config) UnmarshalEnv(es env.EnvSet) error {
+	return env.Unmarshal(es, c)
+}
+
+// Backoff returns the delay before the next attempt of a message
+func (c *Config) Backoff(attempts int) time.Duration {
+	backoff := c.RetryBackoff
+	for i := 1; i < attempts && backoff < c.MaxRetryBackoff; i++ {
+		backoff *= 2
+	}
+
+	if backoff > c.MaxRetryBackoff {
+		return c.MaxRetryBackoff
+	}
+
+	return backoff
+}
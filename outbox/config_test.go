@@ -0,0 +1,18 @@
+package outbox
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackoff(t *testing.T) {
+	c := &Config{RetryBackoff: time.Second, MaxRetryBackoff: 5 * time.Second}
+
+	require.Equal(t, time.Second, c.Backoff(1))
+	require.Equal(t, 2*time.Second, c.Backoff(2))
+	require.Equal(t, 4*time.Second, c.Backoff(3))
+	require.Equal(t, 5*time.Second, c.Backoff(4))
+	require.Equal(t, 5*time.Second, c.Backoff(100))
+}
@@ -0,0 +1,69 @@
+package outbox
+
+import (
+	"context"
+
+	"github.com/easeq/go-service/broker"
+	"github.com/easeq/go-service/db"
+	"github.com/easeq/go-service/db/postgres"
+	"github.com/easeq/go-service/logger"
+)
+
+type Initializer struct {
+	o *Outbox
+}
+
+// NewInitializer returns a new outbox Initialiazer
+func NewInitializer(o *Outbox) *Initializer {
+	return &Initializer{o}
+}
+
+// AddDependency adds necessary service components as dependencies
+func (i *Initializer) AddDependency(dep interface{}) error {
+	switch v := dep.(type) {
+	case logger.Logger:
+		i.o.logger = v
+	case *postgres.Postgres:
+		i.o.db = v.Handle
+	case broker.Broker:
+		i.o.broker = v
+	}
+
+	return nil
+}
+
+// Dependencies returns the string names of service components
+// that are required as dependencies for this component
+func (i *Initializer) Dependencies() []string {
+	return []string{logger.LOGGER, db.DATABASE, broker.BROKER}
+}
+
+// CanRun returns true if the component has anything to Run
+func (i *Initializer) CanRun() bool {
+	return true
+}
+
+// Run creates the outbox table and starts the relay
+func (i *Initializer) Run(ctx context.Context) error {
+	if err := i.o.Migrate(); err != nil {
+		i.o.logger.Errorw("Outbox migration failed", "error", err)
+		return err
+	}
+
+	i.o.logger.Infow("Starting outbox relay")
+	return i.o.Relay(ctx)
+}
+
+// CanStop returns true if the component has anything to Stop
+func (i *Initializer) CanStop() bool {
+	return true
+}
+
+// Stop - stops the outbox relay
+func (i *Initializer) Stop(ctx context.Context) error {
+	i.o.logger.Infow("Stopping outbox relay")
+	i.o.stop.Do(func() {
+		close(i.o.done)
+	})
+	return nil
+}
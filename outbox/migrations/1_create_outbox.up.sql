@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS go_service_outbox (
+    id BIGSERIAL PRIMARY KEY,
+    message_id TEXT NOT NULL,
+    topic TEXT NOT NULL,
+    payload BYTEA NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    failed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS go_service_outbox_pending_idx
+    ON go_service_outbox (topic, id)
+    WHERE failed_at IS NULL;
@@ -0,0 +1,167 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/easeq/go-service/broker"
+	"github.com/easeq/go-service/component"
+	"github.com/easeq/go-service/logger"
+	"github.com/easeq/go-service/utils"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+const (
+	OUTBOX = "outbox"
+
+	// MIGRATIONS_TABLE is the table used to track the outbox schema migrations
+	MIGRATIONS_TABLE = "go_service_outbox_migrations"
+)
+
+var (
+	// ErrNilTx returned when the message is published without a transaction
+	ErrNilTx = errors.New("outbox publish requires a transaction")
+	// ErrDatabaseNotDefined returned when the relay is run without the database dependency
+	ErrDatabaseNotDefined = errors.New("outbox database dependency not defined")
+	// ErrBrokerNotDefined returned when the relay is run without the broker dependency
+	ErrBrokerNotDefined = errors.New("outbox broker dependency not defined")
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Option to pass as arg while creating new outbox
+type Option func(*Outbox)
+
+// PublishOption to pass as arg while publishing a message to the outbox
+type PublishOption func(*message)
+
+// message is the outbox row published by the relay
+type message struct {
+	id        int64
+	messageID string
+	topic     string
+	payload   []byte
+	attempts  int
+}
+
+// Outbox saves messages in the database within the caller's transaction
+// and relays them to the broker once the transaction is committed
+type Outbox struct {
+	i      component.Initializer
+	logger logger.Logger
+	db     *sql.DB
+	broker broker.Broker
+	done   chan struct{}
+	stop   sync.Once
+	*Config
+}
+
+// NewOutbox returns a new outbox using the config from env
+func NewOutbox(opts ...Option) *Outbox {
+	o := &Outbox{
+		Config: NewConfig(),
+		done:   make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	o.i = NewInitializer(o)
+	return o
+}
+
+// WithMessageID sets the idempotency key of the message.
+// The relay publishes the message with broker.WithMessageID, so that
+// retried publishes are deduplicated by the broker or the subscribers.
+func WithMessageID(id string) PublishOption {
+	return func(m *message) {
+		m.messageID = id
+	}
+}
+
+// Publish saves the message in the outbox table within the given transaction.
+// The message is published to the broker by the relay after the transaction is committed.
+func (o *Outbox) Publish(
+	ctx context.Context,
+	tx *sql.Tx,
+	topic string,
+	msg interface{},
+	opts ...PublishOption,
+) error {
+	if tx == nil {
+		return ErrNilTx
+	}
+
+	payload, err := broker.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshalling error: %v", err)
+	}
+
+	m := &message{
+		messageID: utils.NewID(),
+		topic:     topic,
+		payload:   payload,
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	if _, err := tx.ExecContext(
+		ctx,
+		"INSERT INTO go_service_outbox (message_id, topic, payload) VALUES ($1, $2, $3)",
+		m.messageID,
+		m.topic,
+		m.payload,
+	); err != nil {
+		return fmt.Errorf("outbox insert error: %v", err)
+	}
+
+	return nil
+}
+
+// Migrate creates or updates the outbox table
+func (o *Outbox) Migrate() error {
+	if o.db == nil {
+		return ErrDatabaseNotDefined
+	}
+
+	source, err := iofs.New(migrations, "migrations")
+	if err != nil {
+		return fmt.Errorf("outbox migrations source error: %v", err)
+	}
+
+	instance, err := postgres.WithInstance(o.db, &postgres.Config{
+		MigrationsTable: MIGRATIONS_TABLE,
+	})
+	if err != nil {
+		return fmt.Errorf("outbox migrations driver error: %v", err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", source, "postgres", instance)
+	if err != nil {
+		return fmt.Errorf("outbox migrations load error: %v", err)
+	}
+
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("outbox migration failed: %v", err)
+	}
+
+	return nil
+}
+
+func (o *Outbox) HasInitializer() bool {
+	return true
+}
+
+func (o *Outbox) Initializer() component.Initializer {
+	return o.i
+}
@@ -0,0 +1,183 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/easeq/go-service/broker"
+)
+
+const (
+	// selectPending returns the messages due for publishing, excluding the messages
+	// that have an older message of the same topic waiting to be retried.
+	selectPending = `
+SELECT o.id, o.message_id, o.topic, o.payload, o.attempts
+FROM go_service_outbox o
+WHERE o.failed_at IS NULL
+	AND o.next_attempt_at <= NOW()
+	AND NOT EXISTS (
+		SELECT 1 FROM go_service_outbox p
+		WHERE p.topic = o.topic
+			AND p.id < o.id
+			AND p.failed_at IS NULL
+			AND p.next_attempt_at > NOW()
+	)
+ORDER BY o.id
+LIMIT $1
+FOR UPDATE SKIP LOCKED`
+
+	deletePublished = `DELETE FROM go_service_outbox WHERE id = $1`
+
+	updateFailed = `
+UPDATE go_service_outbox
+SET attempts = $2,
+	last_error = $3,
+	next_attempt_at = NOW() + $4::bigint * INTERVAL '1 millisecond',
+	failed_at = CASE WHEN $5::boolean THEN NOW() ELSE NULL END
+WHERE id = $1`
+)
+
+// Relay publishes the outbox messages until the context is cancelled or the outbox is stopped
+func (o *Outbox) Relay(ctx context.Context) error {
+	if o.db == nil {
+		return ErrDatabaseNotDefined
+	}
+
+	if o.broker == nil {
+		return ErrBrokerNotDefined
+	}
+
+	ticker := time.NewTicker(o.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := o.relay(ctx); err != nil {
+			o.logger.Errorw("outbox relay error", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-o.done:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// relay publishes a single batch of messages.
+// Only the relay holding the advisory lock publishes the messages, so that
+// the messages of a topic are published in the order they were saved.
+func (o *Outbox) relay(ctx context.Context) error {
+	tx, err := o.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction error: %v", err)
+	}
+	defer tx.Rollback()
+
+	var locked bool
+	if err := tx.QueryRowContext(
+		ctx,
+		"SELECT pg_try_advisory_xact_lock($1)",
+		o.LockID,
+	).Scan(&locked); err != nil {
+		return fmt.Errorf("advisory lock error: %v", err)
+	}
+
+	if !locked {
+		return nil
+	}
+
+	messages, err := o.pending(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	// Topics with a failed message in this batch,
+	// the rest of their messages are held back to preserve the order.
+	blocked := make(map[string]bool)
+	for _, m := range messages {
+		if blocked[m.topic] {
+			continue
+		}
+
+		if err := o.publish(ctx, m); err != nil {
+			blocked[m.topic] = true
+			if err := o.fail(ctx, tx, m, err); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if _, err := tx.ExecContext(ctx, deletePublished, m.id); err != nil {
+			return fmt.Errorf("outbox delete error: %v", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// pending returns the batch of messages due for publishing
+func (o *Outbox) pending(ctx context.Context, tx *sql.Tx) ([]*message, error) {
+	rows, err := tx.QueryContext(ctx, selectPending, o.BatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("outbox select error: %v", err)
+	}
+	defer rows.Close()
+
+	var messages []*message
+	for rows.Next() {
+		m := new(message)
+		if err := rows.Scan(&m.id, &m.messageID, &m.topic, &m.payload, &m.attempts); err != nil {
+			return nil, fmt.Errorf("outbox scan error: %v", err)
+		}
+
+		messages = append(messages, m)
+	}
+
+	return messages, rows.Err()
+}
+
+// publish sends the message through the broker
+func (o *Outbox) publish(ctx context.Context, m *message) error {
+	return o.broker.Publish(
+		ctx,
+		m.topic,
+		broker.Payload(m.payload),
+		broker.WithMessageID(m.messageID),
+	)
+}
+
+// fail records the failed attempt and schedules the next one.
+// The message is marked as failed once it reaches the maximum attempts.
+func (o *Outbox) fail(ctx context.Context, tx *sql.Tx, m *message, cause error) error {
+	attempts := m.attempts + 1
+	failed := attempts >= o.MaxAttempts
+	backoff := o.Backoff(attempts)
+
+	o.logger.Errorw(
+		"outbox publish error",
+		"topic", m.topic,
+		"id", m.messageID,
+		"attempts", attempts,
+		"failed", failed,
+		"error", cause,
+	)
+
+	if _, err := tx.ExecContext(
+		ctx,
+		updateFailed,
+		m.id,
+		attempts,
+		cause.Error(),
+		backoff.Milliseconds(),
+		failed,
+	); err != nil {
+		return fmt.Errorf("outbox update error: %v", err)
+	}
+
+	return nil
+}
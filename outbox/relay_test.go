@@ -0,0 +1,214 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/easeq/go-service/broker"
+	"github.com/easeq/go-service/logger"
+	"github.com/stretchr/testify/require"
+)
+
+type nopLogger struct {
+	logger.Logger
+}
+
+func (nopLogger) Infow(message string, args ...interface{}) {}
+
+func (nopLogger) Errorw(message string, args ...interface{}) {}
+
+// published is a message received by the stub broker
+type published struct {
+	topic     string
+	payload   string
+	messageID string
+}
+
+// publisher collects the publish options
+type publisher struct {
+	messageID string
+}
+
+func (p *publisher) SetMessageID(id string) {
+	p.messageID = id
+}
+
+// stubBroker records the published messages, the publishes to the failing topics return an error
+type stubBroker struct {
+	broker.Broker
+	failing   map[string]error
+	published []published
+}
+
+func (b *stubBroker) Publish(ctx context.Context, topic string, message interface{}, opts ...broker.PublishOption) error {
+	p := new(publisher)
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	payload, err := broker.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	b.published = append(b.published, published{topic, string(payload), p.messageID})
+	return b.failing[topic]
+}
+
+func newTestOutbox(t *testing.T, b broker.Broker) (*Outbox, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	o := &Outbox{
+		logger: nopLogger{},
+		db:     db,
+		broker: b,
+		done:   make(chan struct{}),
+		Config: &Config{
+			PollInterval:    time.Hour,
+			BatchSize:       100,
+			MaxAttempts:     3,
+			RetryBackoff:    time.Second,
+			MaxRetryBackoff: time.Minute,
+			LockID:          42,
+		},
+	}
+
+	return o, mock
+}
+
+func expectLock(mock sqlmock.Sqlmock, o *Outbox, locked bool) {
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT pg_try_advisory_xact_lock($1)").
+		WithArgs(o.LockID).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_xact_lock"}).AddRow(locked))
+}
+
+func expectPending(mock sqlmock.Sqlmock, o *Outbox, messages ...message) {
+	rows := sqlmock.NewRows([]string{"id", "message_id", "topic", "payload", "attempts"})
+	for _, m := range messages {
+		rows.AddRow(m.id, m.messageID, m.topic, m.payload, m.attempts)
+	}
+
+	mock.ExpectQuery(selectPending).WithArgs(o.BatchSize).WillReturnRows(rows)
+}
+
+func expectDelete(mock sqlmock.Sqlmock, id int64) {
+	mock.ExpectExec(deletePublished).WithArgs(id).WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func TestRelayPublishesInOrder(t *testing.T) {
+	b := new(stubBroker)
+	o, mock := newTestOutbox(t, b)
+
+	expectLock(mock, o, true)
+	expectPending(
+		mock,
+		o,
+		message{id: 1, messageID: "m1", topic: "books", payload: []byte("1")},
+		message{id: 2, messageID: "m2", topic: "authors", payload: []byte("2")},
+		message{id: 3, messageID: "m3", topic: "books", payload: []byte("3")},
+	)
+	expectDelete(mock, 1)
+	expectDelete(mock, 2)
+	expectDelete(mock, 3)
+	mock.ExpectCommit()
+
+	require.NoError(t, o.relay(context.Background()))
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.Equal(t, []published{
+		{"books", "1", "m1"},
+		{"authors", "2", "m2"},
+		{"books", "3", "m3"},
+	}, b.published)
+}
+
+func TestRelayHoldsBackTopicAfterFailure(t *testing.T) {
+	cause := errors.New("broker unavailable")
+	b := &stubBroker{failing: map[string]error{"books": cause}}
+	o, mock := newTestOutbox(t, b)
+
+	expectLock(mock, o, true)
+	expectPending(
+		mock,
+		o,
+		message{id: 1, messageID: "m1", topic: "books", payload: []byte("1")},
+		message{id: 2, messageID: "m2", topic: "authors", payload: []byte("2")},
+		message{id: 3, messageID: "m3", topic: "books", payload: []byte("3")},
+	)
+	mock.ExpectExec(updateFailed).
+		WithArgs(int64(1), 1, cause.Error(), o.RetryBackoff.Milliseconds(), false).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectDelete(mock, 2)
+	mock.ExpectCommit()
+
+	require.NoError(t, o.relay(context.Background()))
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// The later message of the failed topic isn't published before the failed one
+	require.Equal(t, []published{
+		{"books", "1", "m1"},
+		{"authors", "2", "m2"},
+	}, b.published)
+}
+
+func TestRelayMarksFailedAfterMaxAttempts(t *testing.T) {
+	cause := errors.New("broker unavailable")
+	b := &stubBroker{failing: map[string]error{"books": cause}}
+	o, mock := newTestOutbox(t, b)
+
+	expectLock(mock, o, true)
+	expectPending(
+		mock,
+		o,
+		message{id: 1, messageID: "m1", topic: "books", payload: []byte("1"), attempts: o.MaxAttempts - 1},
+	)
+	mock.ExpectExec(updateFailed).
+		WithArgs(int64(1), o.MaxAttempts, cause.Error(), o.Backoff(o.MaxAttempts).Milliseconds(), true).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	require.NoError(t, o.relay(context.Background()))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRelayWithoutLock(t *testing.T) {
+	b := new(stubBroker)
+	o, mock := newTestOutbox(t, b)
+
+	// Another relay holds the advisory lock, the messages aren't read
+	expectLock(mock, o, false)
+	mock.ExpectRollback()
+
+	require.NoError(t, o.relay(context.Background()))
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.Empty(t, b.published)
+}
+
+func TestRelayStop(t *testing.T) {
+	o, mock := newTestOutbox(t, new(stubBroker))
+
+	expectLock(mock, o, false)
+	mock.ExpectRollback()
+
+	i := NewInitializer(o)
+	require.NoError(t, i.Stop(context.Background()))
+	require.NotPanics(t, func() {
+		require.NoError(t, i.Stop(context.Background()))
+	})
+
+	require.NoError(t, o.Relay(context.Background()))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRelayDependencies(t *testing.T) {
+	o, _ := newTestOutbox(t, nil)
+	require.ErrorIs(t, o.Relay(context.Background()), ErrBrokerNotDefined)
+
+	o.db = nil
+	require.ErrorIs(t, o.Relay(context.Background()), ErrDatabaseNotDefined)
+}
@@ -2,9 +2,6 @@ package pool
 
 import (
 	"errors"
-	"sync"
-
-	"github.com/easeq/go-service/logger"
 )
 
 var (
@@ -18,208 +15,76 @@ var (
 	ErrInvalidConnectionPool = errors.New("invalid connection pool")
 )
 
-// Option to pass as arg while creating new service
-type Option func(*ConnectionPool)
-
 // ConnectionPool holds the connections in the pool,
 // alongwith the factory to create a new connection,
-// size of each type of connection in the pool,
-// and the CloseFunc callback used to close a specific connection in the pool
+// the settings of the pool, the HealthCheck callback validating the idle connections
+// and the CloseFunc callback used to close a specific connection in the pool.
+// It adapts Pool[FactoryConn] to the AnyPool interface.
 type ConnectionPool struct {
-	conns     map[string](chan FactoryConn)
-	factory   Factory
-	CloseFunc CloseFunc
-	size      int
-	logger    logger.Logger
-	sync.RWMutex
+	*Pool[FactoryConn]
+	factory     Factory
+	CloseFunc   CloseFunc
+	healthCheck HealthCheck
 }
 
+// ClientConn is the connection borrowed from the ConnectionPool
+type ClientConn = Conn[FactoryConn]
+
 // NewPool creates a new pool with size and factory
 func NewPool(opts ...Option) *ConnectionPool {
-	pool := &ConnectionPool{
-		conns: make(map[string](chan FactoryConn)),
-		size:  10,
-	}
-
+	o := &Options{settings: defaultSettings()}
 	for _, opt := range opts {
-		opt(pool)
+		opt(o)
 	}
 
-	if pool.factory == nil {
+	if o.factory == nil {
 		panic("pool factory cannot be nil")
 	}
 
-	return pool
-}
-
-// WithSize defines the size of the pool
-func WithSize(size int) Option {
-	return func(p *ConnectionPool) {
-		p.size = size
+	p := &ConnectionPool{
+		factory:     o.factory,
+		CloseFunc:   o.closeFunc,
+		healthCheck: o.healthCheck,
 	}
-}
 
-// WithFactory defines the connection creation factory
-func WithFactory(factory Factory) Option {
-	return func(p *ConnectionPool) {
-		p.factory = factory
-	}
-}
+	p.Pool = New(
+		func(address string) (FactoryConn, error) {
+			return p.factory(address)
+		},
+		func(tp *Pool[FactoryConn]) {
+			tp.settings = o.settings
+		},
+		WithCloser(p.close),
+		WithValidator(p.validate),
+	)
 
-// WithCloseFunc passes the CloseFunc callback to the pool
-func WithCloseFunc(closeFunc CloseFunc) Option {
-	return func(p *ConnectionPool) {
-		p.CloseFunc = closeFunc
-	}
+	return p
 }
 
-// WithLogger sets the logger for the pool
-func WithLogger(logger logger.Logger) Option {
-	return func(p *ConnectionPool) {
-		p.logger = logger
-	}
-}
-
-// Creates a new connection channel group
-func (p *ConnectionPool) create(name string) (chan FactoryConn, Factory, error) {
-	p.Lock()
-
-	if p.conns == nil {
-		return nil, nil, ErrConnectionClosed
+// close closes the connection with the CloseFunc, or with Close when it implements io.Closer
+func (p *ConnectionPool) close(conn FactoryConn) error {
+	if p.CloseFunc != nil {
+		return p.CloseFunc(conn)
 	}
 
-	p.conns[name] = make(chan FactoryConn, p.size)
-
-	p.Unlock()
-
-	return p.get(name)
+	return closeConn(conn)
 }
 
-// Get individual connection channel by name and the factory to create connection
-func (p *ConnectionPool) get(name string) (chan FactoryConn, Factory, error) {
-	p.RLock()
-	defer p.RUnlock()
-
-	group, ok := p.conns[name]
-	if !ok {
-		return nil, p.factory, ErrGroupNotExist
+// validate validates the idle connection with the HealthCheck
+func (p *ConnectionPool) validate(conn FactoryConn) error {
+	if p.healthCheck == nil {
+		return nil
 	}
 
-	return group, p.factory, nil
-}
-
-// wraps a the connection provided in a standard Connection
-func (p *ConnectionPool) wrap(address string, conn FactoryConn) Connection {
-	return &ClientConn{
-		p:       p,
-		address: address,
-		conn:    conn,
-	}
+	return p.healthCheck(conn)
 }
 
-// Get creates or returns an existing gRPC client connection
+// Get creates or returns an existing connection of the address
 func (p *ConnectionPool) Get(address string) (Connection, error) {
-	conns, factory, err := p.get(address)
+	conn, err := p.Pool.Get(address)
 	if err != nil {
-		if conns, factory, err = p.create(address); err != nil {
-			return nil, err
-		}
-	}
-
-	if conns == nil {
-		return nil, ErrConnectionClosed
-	}
-
-	select {
-	case conn := <-conns:
-		if conn == nil {
-			return nil, ErrConnectionClosed
-		}
-
-		return p.wrap(address, conn), nil
-	default:
-		conn, err := factory(address)
-		if err != nil {
-			return nil, err
-		}
-
-		return p.wrap(address, conn), nil
+		return nil, err
 	}
-}
-
-func (p *ConnectionPool) add(address string, conn FactoryConn) error {
-	if conn == nil {
-		return ErrConnectionNotExists
-	}
-
-	p.RLock()
-	defer p.RUnlock()
-
-	if p.conns == nil {
-		return p.CloseFunc(conn)
-	}
-
-	// add the connection to the pool or close the connection if the pool is full.
-	select {
-	case p.conns[address] <- conn:
-		return nil
-	default:
-		// Pool group full, close this connection
-		return p.CloseFunc(conn)
-	}
-}
-
-// Close - closes the connection pool and all it's channels
-func (p *ConnectionPool) Close() error {
-	p.Lock()
-	defer p.Unlock()
-
-	conns := p.conns
-	p.conns = nil
-	p.factory = nil
-
-	if conns == nil {
-		return ErrConnectionClosed
-	}
-
-	for key, group := range conns {
-		if group == nil {
-			continue
-		}
-
-		// Close channel
-		close(conns[key])
-
-		for conn := range group {
-			// Close connection
-			if err := p.CloseFunc(conn); err != nil {
-				return err
-			}
-		}
-	}
-
-	return nil
-}
-
-// ClientConn holds the function created by the pool factory method.
-// It also keeps the address, and a reference to the parent pool
-type ClientConn struct {
-	conn    FactoryConn
-	address string
-	p       *ConnectionPool
-}
-
-// Address returns the factory connection address
-func (cc *ClientConn) Address() string {
-	return cc.address
-}
-
-// Conn returns the factory connection
-func (cc *ClientConn) Conn() FactoryConn {
-	return cc.conn
-}
 
-// Close - closes the connection to the gRPC service server
-func (cc *ClientConn) Close() error {
-	return cc.p.add(cc.address, cc.conn)
+	return conn, nil
 }
@@ -0,0 +1,267 @@
+package pool
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testConn struct {
+	id      int64
+	healthy int32
+	closed  int32
+}
+
+// testFactory creates healthy test connections, counting the connections open
+type testFactory struct {
+	created int64
+	open    int64
+	maxOpen int64
+}
+
+func (f *testFactory) factory(address string) (FactoryConn, error) {
+	open := atomic.AddInt64(&f.open, 1)
+	for {
+		max := atomic.LoadInt64(&f.maxOpen)
+		if open <= max || atomic.CompareAndSwapInt64(&f.maxOpen, max, open) {
+			break
+		}
+	}
+
+	return &testConn{id: atomic.AddInt64(&f.created, 1), healthy: 1}, nil
+}
+
+func (f *testFactory) close(conn interface{}) error {
+	c := conn.(*testConn)
+	if !atomic.CompareAndSwapInt32(&c.closed, 0, 1) {
+		return errors.New("connection closed twice")
+	}
+
+	atomic.AddInt64(&f.open, -1)
+	return nil
+}
+
+func healthCheck(conn FactoryConn) error {
+	if atomic.LoadInt32(&conn.(*testConn).healthy) == 0 {
+		return errors.New("unhealthy")
+	}
+
+	return nil
+}
+
+func newTestPool(f *testFactory, opts ...Option) *ConnectionPool {
+	return NewPool(append([]Option{
+		WithFactory(f.factory),
+		WithCloseFunc(f.close),
+		WithHealthCheck(healthCheck),
+	}, opts...)...)
+}
+
+func TestPoolReuse(t *testing.T) {
+	f := &testFactory{}
+	p := newTestPool(f)
+	defer p.Close()
+
+	conn, err := p.Get("a")
+	require.NoError(t, err)
+	require.Equal(t, Stats{InUse: 1, Created: 1}, p.Stats()["a"])
+
+	require.NoError(t, conn.Close())
+	require.ErrorIs(t, conn.Close(), ErrConnectionNotExists)
+	require.Equal(t, Stats{Idle: 1, Created: 1}, p.Stats()["a"])
+
+	again, err := p.Get("a")
+	require.NoError(t, err)
+	require.Same(t, conn.Conn(), again.Conn())
+	require.NoError(t, again.Close())
+}
+
+func TestPoolHealthCheck(t *testing.T) {
+	f := &testFactory{}
+	p := newTestPool(f)
+	defer p.Close()
+
+	conn, err := p.Get("a")
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	// The unhealthy idle connection is closed on borrow and replaced
+	atomic.StoreInt32(&conn.Conn().(*testConn).healthy, 0)
+	again, err := p.Get("a")
+	require.NoError(t, err)
+	require.NotSame(t, conn.Conn(), again.Conn())
+	require.Equal(t, Stats{InUse: 1, Created: 2, Closed: 1}, p.Stats()["a"])
+	require.NoError(t, again.Close())
+}
+
+func TestPoolEviction(t *testing.T) {
+	f := &testFactory{}
+	p := newTestPool(f, WithIdleTimeout(20*time.Millisecond), WithEvictionInterval(5*time.Millisecond))
+	defer p.Close()
+
+	conn, err := p.Get("a")
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	require.Eventually(t, func() bool {
+		return p.Stats()["a"] == Stats{Created: 1, Closed: 1}
+	}, time.Second, 5*time.Millisecond)
+	require.Zero(t, atomic.LoadInt64(&f.open))
+}
+
+func TestPoolMaxLifetime(t *testing.T) {
+	f := &testFactory{}
+	p := newTestPool(f, WithMaxLifetime(10*time.Millisecond), WithEvictionInterval(0))
+	defer p.Close()
+
+	conn, err := p.Get("a")
+	require.NoError(t, err)
+	time.Sleep(20 * time.Millisecond)
+
+	// The expired connection is closed on release
+	require.NoError(t, conn.Close())
+	require.Equal(t, Stats{Created: 1, Closed: 1}, p.Stats()["a"])
+}
+
+func TestPoolMaxActive(t *testing.T) {
+	f := &testFactory{}
+	p := newTestPool(f, WithMaxActive(1), WithWaitTimeout(20*time.Millisecond))
+	defer p.Close()
+
+	conn, err := p.Get("a")
+	require.NoError(t, err)
+
+	_, err = p.Get("a")
+	require.ErrorIs(t, err, ErrPoolExhausted)
+
+	// Other addresses have their own limit
+	other, err := p.Get("b")
+	require.NoError(t, err)
+	require.NoError(t, other.Close())
+
+	// A waiting Get receives the released connection
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		conn.Close()
+	}()
+
+	again, err := p.Get("a")
+	require.NoError(t, err)
+	require.Same(t, conn.Conn(), again.Conn())
+	require.NoError(t, again.Close())
+}
+
+func TestPoolMaxActiveIdle(t *testing.T) {
+	f := &testFactory{}
+	p := newTestPool(f, WithMaxActive(2), WithWaitTimeout(20*time.Millisecond))
+	defer p.Close()
+
+	first, err := p.Get("a")
+	require.NoError(t, err)
+	second, err := p.Get("a")
+	require.NoError(t, err)
+	require.NoError(t, first.Close())
+	require.NoError(t, second.Close())
+
+	// The idle connections count against the limit, they are reused instead of creating new ones
+	conns := []Connection{}
+	for i := 0; i < 2; i++ {
+		conn, err := p.Get("a")
+		require.NoError(t, err)
+		conns = append(conns, conn)
+	}
+
+	_, err = p.Get("a")
+	require.ErrorIs(t, err, ErrPoolExhausted)
+	require.Equal(t, Stats{InUse: 2, Created: 2}, p.Stats()["a"])
+
+	for _, conn := range conns {
+		require.NoError(t, conn.Close())
+	}
+}
+
+func TestPoolMaxActiveClosed(t *testing.T) {
+	f := &testFactory{}
+	p := newTestPool(f, WithSize(0), WithMaxActive(1), WithWaitTimeout(time.Second))
+	defer p.Close()
+
+	conn, err := p.Get("a")
+	require.NoError(t, err)
+
+	// A waiting Get creates a new connection once the released one is closed
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		conn.Close()
+	}()
+
+	again, err := p.Get("a")
+	require.NoError(t, err)
+	require.NotSame(t, conn.Conn(), again.Conn())
+	require.NoError(t, again.Close())
+	require.Equal(t, Stats{Created: 2, Closed: 2}, p.Stats()["a"])
+}
+
+func TestPoolConcurrency(t *testing.T) {
+	const maxActive = 4
+
+	f := &testFactory{}
+	p := newTestPool(f, WithSize(2), WithMaxActive(maxActive))
+
+	var inUse, maxInUse int64
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 20; j++ {
+				conn, err := p.Get("a")
+				require.NoError(t, err)
+
+				n := atomic.AddInt64(&inUse, 1)
+				for {
+					max := atomic.LoadInt64(&maxInUse)
+					if n <= max || atomic.CompareAndSwapInt64(&maxInUse, max, n) {
+						break
+					}
+				}
+
+				atomic.AddInt64(&inUse, -1)
+				require.NoError(t, conn.Close())
+			}
+		}()
+	}
+
+	wg.Wait()
+	require.LessOrEqual(t, maxInUse, int64(maxActive))
+	require.LessOrEqual(t, atomic.LoadInt64(&f.maxOpen), int64(maxActive))
+
+	stats := p.Stats()["a"]
+	require.Zero(t, stats.InUse)
+	require.LessOrEqual(t, stats.Idle, 2)
+	require.Equal(t, stats.Created-stats.Closed, uint64(stats.Idle))
+
+	require.NoError(t, p.Close())
+	require.ErrorIs(t, p.Close(), ErrConnectionClosed)
+	require.Zero(t, atomic.LoadInt64(&f.open))
+
+	_, err := p.Get("a")
+	require.ErrorIs(t, err, ErrConnectionClosed)
+}
+
+func TestPoolCloseInUse(t *testing.T) {
+	f := &testFactory{}
+	p := newTestPool(f)
+
+	conn, err := p.Get("a")
+	require.NoError(t, err)
+	require.NoError(t, p.Close())
+
+	// The connection in use is closed once released
+	require.NoError(t, conn.Close())
+	require.Zero(t, atomic.LoadInt64(&f.open))
+}
@@ -0,0 +1,405 @@
+package pool
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrPoolExhausted returned when no connection is released before the wait timeout
+	ErrPoolExhausted = errors.New("connection pool exhausted")
+)
+
+// Pool holds the connections of type T grouped by address,
+// alongwith the typed factory to create a new connection,
+// the closer and the validator of the connections,
+// and the number of idle and active connections allowed per address.
+// The idle connections are validated on borrow and evicted in the background
+// once they are idle or open for too long.
+type Pool[T any] struct {
+	groups    map[string]*group[T]
+	factory   func(address string) (T, error)
+	closer    func(conn T) error
+	validator func(conn T) error
+	closed    bool
+	done      chan struct{}
+	settings
+	sync.RWMutex
+}
+
+// New creates a new pool of connections of type T created by the factory.
+// The connections implementing io.Closer are closed with Close, unless a closer is provided.
+func New[T any](factory func(address string) (T, error), opts ...PoolOption[T]) *Pool[T] {
+	if factory == nil {
+		panic("pool factory cannot be nil")
+	}
+
+	p := &Pool[T]{
+		groups:   make(map[string]*group[T]),
+		factory:  factory,
+		closer:   closeConn[T],
+		done:     make(chan struct{}),
+		settings: defaultSettings(),
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	if p.evictionInterval > 0 && (p.idleTimeout > 0 || p.maxLifetime > 0) {
+		go p.evict()
+	}
+
+	return p
+}
+
+// closeConn is the default closer, closing the connections implementing io.Closer
+func closeConn[T any](conn T) error {
+	if c, ok := interface{}(conn).(io.Closer); ok {
+		return c.Close()
+	}
+
+	return nil
+}
+
+// group holds the connections of an address
+type group[T any] struct {
+	// open counts the connections in use, idle or being created, limited by the max active limit
+	open int
+	// released is closed to wake the Get calls waiting for a connection
+	// when a connection is released or closed, nil when none is waiting
+	released chan struct{}
+	idle     []*pooledConn[T]
+	inUse    int
+	created  uint64
+	closed   uint64
+	sync.Mutex
+}
+
+// notify wakes the Get calls waiting for a connection of the group, it must be called with the group locked
+func (g *group[T]) notify() {
+	if g.released != nil {
+		close(g.released)
+		g.released = nil
+	}
+}
+
+// wait returns the channel closed once a connection of the group is released or closed,
+// it must be called with the group locked
+func (g *group[T]) wait() <-chan struct{} {
+	if g.released == nil {
+		g.released = make(chan struct{})
+	}
+
+	return g.released
+}
+
+// pooledConn is a connection with its timestamps
+type pooledConn[T any] struct {
+	conn      T
+	createdAt time.Time
+	idleAt    time.Time
+}
+
+// Returns the connection group of the address, creating it if required
+func (p *Pool[T]) group(address string) (*group[T], error) {
+	p.RLock()
+	g, ok := p.groups[address]
+	closed := p.closed
+	p.RUnlock()
+
+	if closed {
+		return nil, ErrConnectionClosed
+	}
+
+	if ok {
+		return g, nil
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	if p.closed {
+		return nil, ErrConnectionClosed
+	}
+
+	// The group may have been created while waiting for the lock
+	if g, ok := p.groups[address]; ok {
+		return g, nil
+	}
+
+	g = &group[T]{}
+	p.groups[address] = g
+	return g, nil
+}
+
+// expired returns true when the connection is idle or open for too long
+func (p *Pool[T]) expired(pc *pooledConn[T], now time.Time) bool {
+	if p.maxLifetime > 0 && now.Sub(pc.createdAt) >= p.maxLifetime {
+		return true
+	}
+
+	return p.idleTimeout > 0 && now.Sub(pc.idleAt) >= p.idleTimeout
+}
+
+// valid returns true when the connection can be reused
+func (p *Pool[T]) valid(pc *pooledConn[T]) bool {
+	if p.expired(pc, time.Now()) {
+		return false
+	}
+
+	return p.validator == nil || p.validator(pc.conn) == nil
+}
+
+// close closes the connection and counts it
+func (p *Pool[T]) close(g *group[T], pc *pooledConn[T]) error {
+	g.Lock()
+	g.closed++
+	g.open--
+	g.notify()
+	g.Unlock()
+
+	err := p.closer(pc.conn)
+	if err != nil && p.logger != nil {
+		p.logger.Errorw("pool connection close error", "error", err)
+	}
+
+	return err
+}
+
+// Get returns a valid idle connection of the address or creates a new one.
+// It waits for a connection to be released when the max active limit is reached.
+func (p *Pool[T]) Get(address string) (*Conn[T], error) {
+	g, err := p.group(address)
+	if err != nil {
+		return nil, err
+	}
+
+	var timeout <-chan time.Time
+	for {
+		g.Lock()
+		if n := len(g.idle); n > 0 {
+			// Reuse the most recently released connection
+			pc := g.idle[n-1]
+			g.idle[n-1] = nil
+			g.idle = g.idle[:n-1]
+			g.Unlock()
+
+			if !p.valid(pc) {
+				p.close(g, pc)
+				continue
+			}
+
+			g.Lock()
+			g.inUse++
+			g.Unlock()
+
+			return &Conn[T]{p: p, g: g, pc: pc, address: address}, nil
+		}
+
+		if p.maxActive <= 0 || g.open < p.maxActive {
+			g.open++
+			g.Unlock()
+			break
+		}
+
+		released := g.wait()
+		g.Unlock()
+
+		if timeout == nil && p.waitTimeout > 0 {
+			timer := time.NewTimer(p.waitTimeout)
+			defer timer.Stop()
+			timeout = timer.C
+		}
+
+		select {
+		case <-released:
+		case <-timeout:
+			return nil, ErrPoolExhausted
+		case <-p.done:
+			return nil, ErrConnectionClosed
+		}
+	}
+
+	conn, err := p.factory(address)
+	if err != nil {
+		g.Lock()
+		g.open--
+		g.notify()
+		g.Unlock()
+
+		return nil, err
+	}
+
+	now := time.Now()
+	pc := &pooledConn[T]{conn: conn, createdAt: now, idleAt: now}
+
+	g.Lock()
+	g.created++
+	g.inUse++
+	g.Unlock()
+
+	return &Conn[T]{p: p, g: g, pc: pc, address: address}, nil
+}
+
+// put adds the connection back to the pool,
+// or closes it when the pool is closed or full, or the connection has expired
+func (p *Pool[T]) put(g *group[T], pc *pooledConn[T]) error {
+	now := time.Now()
+	pc.idleAt = now
+
+	p.RLock()
+	closed := p.closed
+	p.RUnlock()
+
+	g.Lock()
+	g.inUse--
+	if closed || len(g.idle) >= p.size || p.expired(pc, now) {
+		g.Unlock()
+		return p.close(g, pc)
+	}
+
+	g.idle = append(g.idle, pc)
+	g.notify()
+	g.Unlock()
+
+	return nil
+}
+
+// evict closes the expired idle connections periodically until the pool is closed
+func (p *Pool[T]) evict() {
+	ticker := time.NewTicker(p.evictionInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case now := <-ticker.C:
+			p.RLock()
+			groups := make([]*group[T], 0, len(p.groups))
+			for _, g := range p.groups {
+				groups = append(groups, g)
+			}
+			p.RUnlock()
+
+			for _, g := range groups {
+				p.evictGroup(g, now)
+			}
+		}
+	}
+}
+
+// evictGroup closes the expired idle connections of the group
+func (p *Pool[T]) evictGroup(g *group[T], now time.Time) {
+	g.Lock()
+	idle := g.idle[:0]
+	expired := []*pooledConn[T]{}
+	for _, pc := range g.idle {
+		if p.expired(pc, now) {
+			expired = append(expired, pc)
+			continue
+		}
+
+		idle = append(idle, pc)
+	}
+
+	for i := len(idle); i < len(g.idle); i++ {
+		g.idle[i] = nil
+	}
+
+	g.idle = idle
+	g.Unlock()
+
+	for _, pc := range expired {
+		p.close(g, pc)
+	}
+}
+
+// Stats returns the connection stats of each address
+func (p *Pool[T]) Stats() map[string]Stats {
+	p.RLock()
+	defer p.RUnlock()
+
+	stats := make(map[string]Stats, len(p.groups))
+	for address, g := range p.groups {
+		g.Lock()
+		stats[address] = Stats{
+			InUse:   g.inUse,
+			Idle:    len(g.idle),
+			Created: g.created,
+			Closed:  g.closed,
+		}
+		g.Unlock()
+	}
+
+	return stats
+}
+
+// Close - closes the connection pool and all the idle connections.
+// The connections in use are closed once they are released.
+func (p *Pool[T]) Close() error {
+	p.Lock()
+	if p.closed {
+		p.Unlock()
+		return ErrConnectionClosed
+	}
+
+	p.closed = true
+	close(p.done)
+	groups := p.groups
+	p.Unlock()
+
+	var err error
+	for _, g := range groups {
+		g.Lock()
+		idle := g.idle
+		g.idle = nil
+		g.Unlock()
+
+		for _, pc := range idle {
+			if cerr := p.close(g, pc); cerr != nil && err == nil {
+				err = cerr
+			}
+		}
+	}
+
+	return err
+}
+
+// Conn is a connection borrowed from the pool.
+// It must be closed to release the connection back to the pool.
+type Conn[T any] struct {
+	address  string
+	p        *Pool[T]
+	g        *group[T]
+	pc       *pooledConn[T]
+	released bool
+	sync.Mutex
+}
+
+// Address returns the connection address
+func (c *Conn[T]) Address() string {
+	return c.address
+}
+
+// Conn returns the connection created by the factory
+func (c *Conn[T]) Conn() T {
+	return c.pc.conn
+}
+
+// Close releases the connection back to the pool, closing it if it can't be reused
+func (c *Conn[T]) Close() error {
+	c.Lock()
+	if c.released {
+		c.Unlock()
+		return ErrConnectionNotExists
+	}
+
+	c.released = true
+	c.Unlock()
+
+	return c.p.put(c.g, c.pc)
+}
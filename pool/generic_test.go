@@ -0,0 +1,87 @@
+package pool
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// tcpClient is a custom client pooled without type assertions
+type tcpClient struct {
+	address string
+	broken  bool
+	closed  bool
+}
+
+func (c *tcpClient) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestTypedPool(t *testing.T) {
+	p := New(func(address string) (*tcpClient, error) {
+		return &tcpClient{address: address}, nil
+	}, WithValidator(func(c *tcpClient) error {
+		if c.broken {
+			return errors.New("broken")
+		}
+
+		return nil
+	}))
+
+	conn, err := p.Get("localhost:9000")
+	require.NoError(t, err)
+
+	client := conn.Conn()
+	require.Equal(t, "localhost:9000", client.address)
+	require.NoError(t, conn.Close())
+
+	// The broken client is closed with io.Closer on borrow
+	client.broken = true
+	conn, err = p.Get("localhost:9000")
+	require.NoError(t, err)
+	require.True(t, client.closed)
+	require.NotSame(t, client, conn.Conn())
+	require.NoError(t, conn.Close())
+
+	require.NoError(t, p.Close())
+	require.True(t, conn.Conn().closed)
+}
+
+func TestTypedPoolCloser(t *testing.T) {
+	closed := []int{}
+	p := New(func(address string) (int, error) {
+		return len(closed), nil
+	}, WithOptions[int](WithSize(0)), WithCloser(func(conn int) error {
+		closed = append(closed, conn)
+		return nil
+	}))
+
+	conn, err := p.Get("a")
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+	require.Equal(t, []int{0}, closed)
+	require.NoError(t, p.Close())
+}
+
+func TestConnectionPoolAdapter(t *testing.T) {
+	var p AnyPool = NewPool(WithFactory(func(address string) (FactoryConn, error) {
+		return &tcpClient{address: address}, nil
+	}))
+
+	conn, err := p.Get("localhost:9000")
+	require.NoError(t, err)
+	require.Equal(t, "localhost:9000", conn.Conn().(*tcpClient).address)
+	require.NoError(t, conn.Close())
+
+	// The CloseFunc set after the creation of the pool is used
+	closed := 0
+	p.(*ConnectionPool).CloseFunc = func(conn interface{}) error {
+		closed++
+		return nil
+	}
+
+	require.NoError(t, p.Close())
+	require.Equal(t, 1, closed)
+}
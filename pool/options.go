@@ -0,0 +1,139 @@
+package pool
+
+import (
+	"time"
+
+	"github.com/easeq/go-service/logger"
+)
+
+// Option to pass as arg while creating new service
+type Option func(*Options)
+
+// PoolOption to pass as arg while creating a new Pool of connections of type T
+type PoolOption[T any] func(*Pool[T])
+
+// Options holds the options set by the Option functions,
+// the settings shared by the pools and the callbacks of the ConnectionPool
+type Options struct {
+	settings
+	factory     Factory
+	closeFunc   CloseFunc
+	healthCheck HealthCheck
+}
+
+// settings holds the pool settings shared by the pools
+type settings struct {
+	size             int
+	maxActive        int
+	waitTimeout      time.Duration
+	idleTimeout      time.Duration
+	maxLifetime      time.Duration
+	evictionInterval time.Duration
+	logger           logger.Logger
+}
+
+// defaultSettings returns the default pool settings
+func defaultSettings() settings {
+	return settings{
+		size:             10,
+		evictionInterval: time.Minute,
+	}
+}
+
+// WithSize defines the maximum number of idle connections per address
+func WithSize(size int) Option {
+	return func(o *Options) {
+		o.size = size
+	}
+}
+
+// WithMaxActive limits the number of open connections per address, in use or idle, 0 means no limit.
+// Get waits for a connection to be released once the limit is reached.
+func WithMaxActive(maxActive int) Option {
+	return func(o *Options) {
+		o.maxActive = maxActive
+	}
+}
+
+// WithWaitTimeout sets how long Get waits for a connection when the max active limit is reached,
+// 0 waits until a connection is released
+func WithWaitTimeout(timeout time.Duration) Option {
+	return func(o *Options) {
+		o.waitTimeout = timeout
+	}
+}
+
+// WithIdleTimeout closes the connections idle in the pool for longer than the timeout
+func WithIdleTimeout(timeout time.Duration) Option {
+	return func(o *Options) {
+		o.idleTimeout = timeout
+	}
+}
+
+// WithMaxLifetime closes the connections open for longer than the lifetime, once they are released
+func WithMaxLifetime(lifetime time.Duration) Option {
+	return func(o *Options) {
+		o.maxLifetime = lifetime
+	}
+}
+
+// WithEvictionInterval sets how often the expired idle connections are closed, 0 disables the eviction
+func WithEvictionInterval(interval time.Duration) Option {
+	return func(o *Options) {
+		o.evictionInterval = interval
+	}
+}
+
+// WithLogger sets the logger for the pool
+func WithLogger(logger logger.Logger) Option {
+	return func(o *Options) {
+		o.logger = logger
+	}
+}
+
+// WithOptions applies the options of the pool settings, e.g. WithSize, to a Pool of connections of type T
+func WithOptions[T any](opts ...Option) PoolOption[T] {
+	return func(p *Pool[T]) {
+		o := &Options{settings: p.settings}
+		for _, opt := range opts {
+			opt(o)
+		}
+
+		p.settings = o.settings
+	}
+}
+
+// WithCloser sets the callback closing the connections of a Pool
+func WithCloser[T any](closer func(conn T) error) PoolOption[T] {
+	return func(p *Pool[T]) {
+		p.closer = closer
+	}
+}
+
+// WithValidator sets the callback validating the idle connections of a Pool on borrow
+func WithValidator[T any](validator func(conn T) error) PoolOption[T] {
+	return func(p *Pool[T]) {
+		p.validator = validator
+	}
+}
+
+// WithFactory defines the connection creation factory
+func WithFactory(factory Factory) Option {
+	return func(o *Options) {
+		o.factory = factory
+	}
+}
+
+// WithCloseFunc passes the CloseFunc callback to the pool
+func WithCloseFunc(closeFunc CloseFunc) Option {
+	return func(o *Options) {
+		o.closeFunc = closeFunc
+	}
+}
+
+// WithHealthCheck sets the callback validating the idle connections of the ConnectionPool on borrow
+func WithHealthCheck(healthCheck HealthCheck) Option {
+	return func(o *Options) {
+		o.healthCheck = healthCheck
+	}
+}
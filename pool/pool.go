@@ -7,8 +7,21 @@ var (
 	ErrConnectionClosed = errors.New("connections closed")
 )
 
-// Pool interface to create new pool implementations
-type Pool interface {
+// Stats holds the connection stats of an address
+type Stats struct {
+	// InUse is the number of connections borrowed from the pool
+	InUse int
+	// Idle is the number of connections waiting in the pool
+	Idle int
+	// Created is the number of connections created by the factory
+	Created uint64
+	// Closed is the number of connections closed by the pool
+	Closed uint64
+}
+
+// AnyPool interface to create new pool implementations of untyped connections, e.g. ConnectionPool.
+// The typed connections are pooled with Pool[T].
+type AnyPool interface {
 	// Get connection
 	Get(address string) (Connection, error)
 	// Close the pool
@@ -33,3 +46,6 @@ type Factory func(address string) (FactoryConn, error)
 
 // CloseFunc to close the connection in the pool
 type CloseFunc func(conn interface{}) error
+
+// HealthCheck callback returns an error when the connection can't be reused
+type HealthCheck func(conn FactoryConn) error
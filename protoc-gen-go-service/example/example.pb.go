@@ -154,6 +154,53 @@ func (*Empty) Descriptor() ([]byte, []int) {
 	return file_example_example_proto_rawDescGZIP(), []int{2}
 }
 
+type Processed struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Arg string `protobuf:"bytes,1,opt,name=arg,proto3" json:"arg,omitempty"`
+}
+
+func (x *Processed) Reset() {
+	*x = Processed{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_example_example_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Processed) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Processed) ProtoMessage() {}
+
+func (x *Processed) ProtoReflect() protoreflect.Message {
+	mi := &file_example_example_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Processed.ProtoReflect.Descriptor instead.
+func (*Processed) Descriptor() ([]byte, []int) {
+	return file_example_example_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Processed) GetArg() string {
+	if x != nil {
+		return x.Arg
+	}
+	return ""
+}
+
 var File_example_example_proto protoreflect.FileDescriptor
 
 var file_example_example_proto_rawDesc = []byte{
@@ -166,31 +213,39 @@ var file_example_example_proto_rawDesc = []byte{
 	0x0a, 0x03, 0x61, 0x72, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x61, 0x72, 0x67,
 	0x22, 0x24, 0x0a, 0x08, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07,
 	0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73,
-	0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x22, 0x07, 0x0a, 0x05, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x32,
-	0xe7, 0x02, 0x0a, 0x0e, 0x45, 0x78, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69,
-	0x63, 0x65, 0x12, 0x24, 0x0a, 0x05, 0x55, 0x6e, 0x61, 0x72, 0x79, 0x12, 0x0b, 0x2e, 0x76, 0x31,
-	0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x3c, 0x0a, 0x08, 0x4e, 0x6f, 0x52, 0x65,
-	0x74, 0x75, 0x72, 0x6e, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x16, 0x2e, 0x67,
-	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45,
-	0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x2d, 0x0a, 0x0c, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72,
-	0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x0b, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x2d, 0x0a, 0x0c, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x53,
-	0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x0b, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x22, 0x07, 0x0a, 0x05, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22,
+	0x38, 0x0a, 0x09, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x65, 0x64, 0x12, 0x10, 0x0a, 0x03,
+	0x61, 0x72, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x61, 0x72, 0x67, 0x3a, 0x19,
+	0x9a, 0xd3, 0xe4, 0x93, 0x02, 0x13, 0x0a, 0x11, 0x65, 0x78, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x65, 0x64, 0x32, 0xa2, 0x03, 0x0a, 0x0e, 0x45, 0x78,
+	0x61, 0x6d, 0x70, 0x6c, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x50, 0x0a, 0x05,
+	0x55, 0x6e, 0x61, 0x72, 0x79, 0x12, 0x0b, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65,
 	0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x22, 0x00, 0x28, 0x01, 0x12, 0x36, 0x0a, 0x13, 0x42, 0x69, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74,
-	0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x0b, 0x2e, 0x76, 0x31,
+	0x22, 0x2c, 0xaa, 0xd3, 0xe4, 0x93, 0x02, 0x26, 0x0a, 0x02, 0x32, 0x73, 0x1a, 0x0b, 0x55, 0x4e,
+	0x41, 0x56, 0x41, 0x49, 0x4c, 0x41, 0x42, 0x4c, 0x45, 0x1a, 0x11, 0x44, 0x45, 0x41, 0x44, 0x4c,
+	0x49, 0x4e, 0x45, 0x5f, 0x45, 0x58, 0x43, 0x45, 0x45, 0x44, 0x45, 0x44, 0x28, 0x01, 0x12, 0x3c,
+	0x0a, 0x08, 0x4e, 0x6f, 0x52, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70,
+	0x74, 0x79, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x2d, 0x0a, 0x0c,
+	0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x0b, 0x2e, 0x76,
+	0x31, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x76, 0x31, 0x2e, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x2d, 0x0a, 0x0c, 0x43,
+	0x6c, 0x69, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x0b, 0x2e, 0x76, 0x31,
 	0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x1a, 0x5b, 0x92, 0xd3,
-	0xe4, 0x93, 0x02, 0x55, 0x0a, 0x13, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2d, 0x6e, 0x61,
-	0x6d, 0x65, 0x2d, 0x75, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x12, 0x08, 0x42, 0x41, 0x53, 0x45, 0x5f,
-	0x55, 0x52, 0x4c, 0x1a, 0x21, 0x2f, 0x79, 0x6f, 0x75, 0x72, 0x2f, 0x70, 0x61, 0x74, 0x68, 0x2f,
-	0x77, 0x69, 0x74, 0x68, 0x2f, 0x6f, 0x72, 0x2f, 0x77, 0x69, 0x74, 0x68, 0x6f, 0x75, 0x74, 0x2f,
-	0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x22, 0x11, 0x2f, 0x79, 0x6f, 0x75, 0x72, 0x2f, 0x70, 0x72,
-	0x65, 0x66, 0x69, 0x78, 0x2f, 0x70, 0x61, 0x74, 0x68, 0x42, 0x0c, 0x5a, 0x0a, 0x2e, 0x2f, 0x3b,
-	0x65, 0x78, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x01, 0x12, 0x36, 0x0a, 0x13, 0x42, 0x69,
+	0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x53, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x12, 0x0b, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c,
+	0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x01,
+	0x30, 0x01, 0x1a, 0x6a, 0x92, 0xd3, 0xe4, 0x93, 0x02, 0x55, 0x0a, 0x13, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2d, 0x6e, 0x61, 0x6d, 0x65, 0x2d, 0x75, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x12,
+	0x08, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x55, 0x52, 0x4c, 0x1a, 0x21, 0x2f, 0x79, 0x6f, 0x75, 0x72,
+	0x2f, 0x70, 0x61, 0x74, 0x68, 0x2f, 0x77, 0x69, 0x74, 0x68, 0x2f, 0x6f, 0x72, 0x2f, 0x77, 0x69,
+	0x74, 0x68, 0x6f, 0x75, 0x74, 0x2f, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x22, 0x11, 0x2f, 0x79,
+	0x6f, 0x75, 0x72, 0x2f, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x2f, 0x70, 0x61, 0x74, 0x68, 0xa2,
+	0xd3, 0xe4, 0x93, 0x02, 0x09, 0x0a, 0x07, 0x65, 0x78, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x42, 0x0c,
+	0x5a, 0x0a, 0x2e, 0x2f, 0x3b, 0x65, 0x78, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -205,21 +260,22 @@ func file_example_example_proto_rawDescGZIP() []byte {
 	return file_example_example_proto_rawDescData
 }
 
-var file_example_example_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_example_example_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_example_example_proto_goTypes = []interface{}{
 	(*Request)(nil),     // 0: v1.Request
 	(*Response)(nil),    // 1: v1.Response
 	(*Empty)(nil),       // 2: v1.Empty
-	(*empty.Empty)(nil), // 3: google.protobuf.Empty
+	(*Processed)(nil),   // 3: v1.Processed
+	(*empty.Empty)(nil), // 4: google.protobuf.Empty
 }
 var file_example_example_proto_depIdxs = []int32{
 	0, // 0: v1.ExampleService.Unary:input_type -> v1.Request
-	3, // 1: v1.ExampleService.NoReturn:input_type -> google.protobuf.Empty
+	4, // 1: v1.ExampleService.NoReturn:input_type -> google.protobuf.Empty
 	0, // 2: v1.ExampleService.ServerStream:input_type -> v1.Request
 	0, // 3: v1.ExampleService.ClientStream:input_type -> v1.Request
 	0, // 4: v1.ExampleService.BiDirectionalStream:input_type -> v1.Request
 	1, // 5: v1.ExampleService.Unary:output_type -> v1.Response
-	3, // 6: v1.ExampleService.NoReturn:output_type -> google.protobuf.Empty
+	4, // 6: v1.ExampleService.NoReturn:output_type -> google.protobuf.Empty
 	1, // 7: v1.ExampleService.ServerStream:output_type -> v1.Response
 	1, // 8: v1.ExampleService.ClientStream:output_type -> v1.Response
 	1, // 9: v1.ExampleService.BiDirectionalStream:output_type -> v1.Response
@@ -272,6 +328,18 @@ func file_example_example_proto_init() {
 				return nil
 			}
 		}
+		file_example_example_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Processed); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -279,7 +347,7 @@ func file_example_example_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_example_example_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   4,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
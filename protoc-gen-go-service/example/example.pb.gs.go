@@ -7,17 +7,22 @@ package example
 import (
 	"context"
 
+	"github.com/easeq/go-service/broker"
 	"github.com/easeq/go-service/client"
 	"github.com/easeq/go-service/server"
+	grpc_server "github.com/easeq/go-service/server/grpc"
 	"os"
+	"time"
+
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
 )
 
 type ExampleServiceGSClient interface {
 	Unary(ctx context.Context, in *Request, opts ...client.CallOption) (*Response, error)
 	NoReturn(ctx context.Context, in *Empty, opts ...client.CallOption) (*Empty, error)
-	ServerStream(ctx context.Context, in *Request, opts ...client.CallOption) (client.StreamClient, error)
-	ClientStream(ctx context.Context, in *Request, opts ...client.CallOption) (client.StreamClient, error)
-	BiDirectionalStream(ctx context.Context, in *Request, opts ...client.CallOption) (client.StreamClient, error)
+	ServerStream(ctx context.Context, in *Request, opts ...client.CallOption) (ExampleService_ServerStreamGSClient, error)
+	ClientStream(ctx context.Context, opts ...client.CallOption) (ExampleService_ClientStreamGSClient, error)
+	BiDirectionalStream(ctx context.Context, opts ...client.CallOption) (ExampleService_BiDirectionalStreamGSClient, error)
 }
 
 type exampleServiceGSClient struct {
@@ -39,6 +44,10 @@ func (sc *exampleServiceGSClient) GetDialOptions() []client.DialOption {
 }
 
 func (sc *exampleServiceGSClient) Unary(ctx context.Context, in *Request, opts ...client.CallOption) (*Response, error) {
+	opts = append([]client.CallOption{
+		client.WithTimeout(2 * time.Second),
+		client.WithRetryableCodes("UNAVAILABLE", "DEADLINE_EXCEEDED"),
+	}, opts...)
 	res := new(Response)
 	err := sc.Call(ctx, sc, "/v1.ExampleService/Unary", in, res, opts...)
 	if err != nil {
@@ -58,31 +67,232 @@ func (sc *exampleServiceGSClient) NoReturn(ctx context.Context, in *Empty, opts
 	return res, nil
 }
 
-func (sc *exampleServiceGSClient) ServerStream(ctx context.Context, in *Request, opts ...client.CallOption) (client.StreamClient, error) {
+func (sc *exampleServiceGSClient) ServerStream(ctx context.Context, in *Request, opts ...client.CallOption) (ExampleService_ServerStreamGSClient, error) {
 	stream, err := sc.Stream(ctx, sc, &ExampleService_ServiceDesc.Streams[0], "/v1.ExampleService/ServerStream", in, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	return stream, nil
+	return &exampleServiceServerStreamGSClient{stream}, nil
+}
+
+type ExampleService_ServerStreamGSClient interface {
+	Recv() (*Response, error)
+	client.Stream
+}
+
+type exampleServiceServerStreamGSClient struct {
+	client.StreamClient
+}
+
+func (x *exampleServiceServerStreamGSClient) Recv() (*Response, error) {
+	m := new(Response)
+	if err := x.StreamClient.Recv(m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
 }
 
-func (sc *exampleServiceGSClient) ClientStream(ctx context.Context, in *Request, opts ...client.CallOption) (client.StreamClient, error) {
-	stream, err := sc.Stream(ctx, sc, &ExampleService_ServiceDesc.Streams[1], "/v1.ExampleService/ClientStream", in, opts...)
+func (sc *exampleServiceGSClient) ClientStream(ctx context.Context, opts ...client.CallOption) (ExampleService_ClientStreamGSClient, error) {
+	stream, err := sc.Stream(ctx, sc, &ExampleService_ServiceDesc.Streams[1], "/v1.ExampleService/ClientStream", nil, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	return stream, nil
+	return &exampleServiceClientStreamGSClient{stream}, nil
+}
+
+type ExampleService_ClientStreamGSClient interface {
+	Send(*Request) error
+	CloseAndRecv() (*Response, error)
+	client.Stream
+}
+
+type exampleServiceClientStreamGSClient struct {
+	client.StreamClient
+}
+
+func (x *exampleServiceClientStreamGSClient) Send(m *Request) error {
+	return x.StreamClient.Send(m)
+}
+
+func (x *exampleServiceClientStreamGSClient) CloseAndRecv() (*Response, error) {
+	m := new(Response)
+	if err := x.StreamClient.CloseAndRecv(m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
 }
 
-func (sc *exampleServiceGSClient) BiDirectionalStream(ctx context.Context, in *Request, opts ...client.CallOption) (client.StreamClient, error) {
-	stream, err := sc.Stream(ctx, sc, &ExampleService_ServiceDesc.Streams[2], "/v1.ExampleService/BiDirectionalStream", in, opts...)
+func (sc *exampleServiceGSClient) BiDirectionalStream(ctx context.Context, opts ...client.CallOption) (ExampleService_BiDirectionalStreamGSClient, error) {
+	stream, err := sc.Stream(ctx, sc, &ExampleService_ServiceDesc.Streams[2], "/v1.ExampleService/BiDirectionalStream", nil, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	return stream, nil
+	return &exampleServiceBiDirectionalStreamGSClient{stream}, nil
+}
+
+type ExampleService_BiDirectionalStreamGSClient interface {
+	Send(*Request) error
+	Recv() (*Response, error)
+	client.Stream
+}
+
+type exampleServiceBiDirectionalStreamGSClient struct {
+	client.StreamClient
+}
+
+func (x *exampleServiceBiDirectionalStreamGSClient) Send(m *Request) error {
+	return x.StreamClient.Send(m)
+}
+
+func (x *exampleServiceBiDirectionalStreamGSClient) Recv() (*Response, error) {
+	m := new(Response)
+	if err := x.StreamClient.Recv(m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// RegisterExampleServiceGSServer registers the service implementation on the gRPC server
+func RegisterExampleServiceGSServer(s *grpc_server.Grpc, srv ExampleServiceServer) {
+	RegisterExampleServiceServer(s.Server, srv)
+	s.SetMethodOptions("/v1.ExampleService/Unary", grpc_server.MethodOptions{
+		Timeout:      2 * time.Second,
+		AuthRequired: true,
+	})
+}
+
+// NewExampleServiceGSServer creates the gRPC server with the service implementation registered
+func NewExampleServiceGSServer(srv ExampleServiceServer, opts ...grpc_server.Option) *grpc_server.Grpc {
+	s := grpc_server.NewGrpc(opts...)
+	RegisterExampleServiceGSServer(s, srv)
+
+	return s
+}
+
+// ProcessedTopic is the topic of the Processed events
+const ProcessedTopic = "example.processed"
+
+// PublishProcessed publishes the Processed event encoded with protobuf
+func PublishProcessed(ctx context.Context, b broker.Broker, m *Processed, opts ...broker.PublishOption) error {
+	return b.Publish(ctx, ProcessedTopic, broker.Proto(m), opts...)
+}
+
+// SubscribeProcessed subscribes the handler to the Processed events
+func SubscribeProcessed(ctx context.Context, b broker.Broker, handler func(context.Context, *Processed) error, opts ...broker.SubscribeOption) error {
+	return b.Subscribe(ctx, ProcessedTopic, broker.HandlerFunc(func(ctx context.Context, msg *broker.Message) error {
+		m := new(Processed)
+		if err := broker.UnmarshalProto(msg, m); err != nil {
+			return err
+		}
+
+		return handler(ctx, m)
+	}), opts...)
+}
+
+// ExampleServiceUnaryTopic is the topic of the Request events
+const ExampleServiceUnaryTopic = "example.Unary"
+
+// PublishExampleServiceUnary publishes the Request event encoded with protobuf
+func PublishExampleServiceUnary(ctx context.Context, b broker.Broker, m *Request, opts ...broker.PublishOption) error {
+	return b.Publish(ctx, ExampleServiceUnaryTopic, broker.Proto(m), opts...)
+}
+
+// SubscribeExampleServiceUnary subscribes the handler to the Request events
+func SubscribeExampleServiceUnary(ctx context.Context, b broker.Broker, handler func(context.Context, *Request) error, opts ...broker.SubscribeOption) error {
+	return b.Subscribe(ctx, ExampleServiceUnaryTopic, broker.HandlerFunc(func(ctx context.Context, msg *broker.Message) error {
+		m := new(Request)
+		if err := broker.UnmarshalProto(msg, m); err != nil {
+			return err
+		}
+
+		return handler(ctx, m)
+	}), opts...)
+}
+
+// ExampleServiceNoReturnTopic is the topic of the Empty events
+const ExampleServiceNoReturnTopic = "example.NoReturn"
+
+// PublishExampleServiceNoReturn publishes the Empty event encoded with protobuf
+func PublishExampleServiceNoReturn(ctx context.Context, b broker.Broker, m *emptypb.Empty, opts ...broker.PublishOption) error {
+	return b.Publish(ctx, ExampleServiceNoReturnTopic, broker.Proto(m), opts...)
+}
+
+// SubscribeExampleServiceNoReturn subscribes the handler to the Empty events
+func SubscribeExampleServiceNoReturn(ctx context.Context, b broker.Broker, handler func(context.Context, *emptypb.Empty) error, opts ...broker.SubscribeOption) error {
+	return b.Subscribe(ctx, ExampleServiceNoReturnTopic, broker.HandlerFunc(func(ctx context.Context, msg *broker.Message) error {
+		m := new(emptypb.Empty)
+		if err := broker.UnmarshalProto(msg, m); err != nil {
+			return err
+		}
+
+		return handler(ctx, m)
+	}), opts...)
+}
+
+// ExampleServiceServerStreamTopic is the topic of the Request events
+const ExampleServiceServerStreamTopic = "example.ServerStream"
+
+// PublishExampleServiceServerStream publishes the Request event encoded with protobuf
+func PublishExampleServiceServerStream(ctx context.Context, b broker.Broker, m *Request, opts ...broker.PublishOption) error {
+	return b.Publish(ctx, ExampleServiceServerStreamTopic, broker.Proto(m), opts...)
+}
+
+// SubscribeExampleServiceServerStream subscribes the handler to the Request events
+func SubscribeExampleServiceServerStream(ctx context.Context, b broker.Broker, handler func(context.Context, *Request) error, opts ...broker.SubscribeOption) error {
+	return b.Subscribe(ctx, ExampleServiceServerStreamTopic, broker.HandlerFunc(func(ctx context.Context, msg *broker.Message) error {
+		m := new(Request)
+		if err := broker.UnmarshalProto(msg, m); err != nil {
+			return err
+		}
+
+		return handler(ctx, m)
+	}), opts...)
+}
+
+// ExampleServiceClientStreamTopic is the topic of the Request events
+const ExampleServiceClientStreamTopic = "example.ClientStream"
+
+// PublishExampleServiceClientStream publishes the Request event encoded with protobuf
+func PublishExampleServiceClientStream(ctx context.Context, b broker.Broker, m *Request, opts ...broker.PublishOption) error {
+	return b.Publish(ctx, ExampleServiceClientStreamTopic, broker.Proto(m), opts...)
+}
+
+// SubscribeExampleServiceClientStream subscribes the handler to the Request events
+func SubscribeExampleServiceClientStream(ctx context.Context, b broker.Broker, handler func(context.Context, *Request) error, opts ...broker.SubscribeOption) error {
+	return b.Subscribe(ctx, ExampleServiceClientStreamTopic, broker.HandlerFunc(func(ctx context.Context, msg *broker.Message) error {
+		m := new(Request)
+		if err := broker.UnmarshalProto(msg, m); err != nil {
+			return err
+		}
+
+		return handler(ctx, m)
+	}), opts...)
+}
+
+// ExampleServiceBiDirectionalStreamTopic is the topic of the Request events
+const ExampleServiceBiDirectionalStreamTopic = "example.BiDirectionalStream"
+
+// PublishExampleServiceBiDirectionalStream publishes the Request event encoded with protobuf
+func PublishExampleServiceBiDirectionalStream(ctx context.Context, b broker.Broker, m *Request, opts ...broker.PublishOption) error {
+	return b.Publish(ctx, ExampleServiceBiDirectionalStreamTopic, broker.Proto(m), opts...)
+}
+
+// SubscribeExampleServiceBiDirectionalStream subscribes the handler to the Request events
+func SubscribeExampleServiceBiDirectionalStream(ctx context.Context, b broker.Broker, handler func(context.Context, *Request) error, opts ...broker.SubscribeOption) error {
+	return b.Subscribe(ctx, ExampleServiceBiDirectionalStreamTopic, broker.HandlerFunc(func(ctx context.Context, msg *broker.Message) error {
+		m := new(Request)
+		if err := broker.UnmarshalProto(msg, m); err != nil {
+			return err
+		}
+
+		return handler(ctx, m)
+	}), opts...)
 }
 
 func AddExampleServiceServerRegistryTags(server server.Server) {
@@ -90,10 +300,7 @@ func AddExampleServiceServerRegistryTags(server server.Server) {
 		"traefik.http.routers.service-name-unique.rule=Host(`" + os.Getenv("BASE_URL") + "`) && PathPrefix(`/your/path/with/or/without/prefix`)",
 		"traefik.http.middlewares.service-name-unique-stripprefix.stripprefix.prefixes=/your/prefix/path",
 		"traefik.http.routers.service-name-unique.middlewares=service-name-unique-stripprefix@consulcatalog",
-	}
-
-	if len(tags) > 0 {
-		tags = append(tags, "traefik.enable=true")
+		"traefik.enable=true",
 	}
 
 	server.AddRegistryTags(tags...)
@@ -0,0 +1,63 @@
+// Code generated by protoc-gen-go_service. DO NOT EDIT.
+// versions:
+//	protoc	v3.12.3
+
+package example
+
+import (
+	"context"
+
+	"github.com/easeq/go-service/client"
+)
+
+// MockExampleServiceGSClient is a mock of ExampleServiceGSClient for unit tests.
+// The calls are passed to the functions set, the methods without one return client.ErrNotMocked.
+type MockExampleServiceGSClient struct {
+	UnaryFunc               func(ctx context.Context, in *Request, opts ...client.CallOption) (*Response, error)
+	NoReturnFunc            func(ctx context.Context, in *Empty, opts ...client.CallOption) (*Empty, error)
+	ServerStreamFunc        func(ctx context.Context, in *Request, opts ...client.CallOption) (ExampleService_ServerStreamGSClient, error)
+	ClientStreamFunc        func(ctx context.Context, opts ...client.CallOption) (ExampleService_ClientStreamGSClient, error)
+	BiDirectionalStreamFunc func(ctx context.Context, opts ...client.CallOption) (ExampleService_BiDirectionalStreamGSClient, error)
+}
+
+var _ ExampleServiceGSClient = (*MockExampleServiceGSClient)(nil)
+
+func (m *MockExampleServiceGSClient) Unary(ctx context.Context, in *Request, opts ...client.CallOption) (*Response, error) {
+	if m.UnaryFunc == nil {
+		return nil, client.ErrNotMocked
+	}
+
+	return m.UnaryFunc(ctx, in, opts...)
+}
+
+func (m *MockExampleServiceGSClient) NoReturn(ctx context.Context, in *Empty, opts ...client.CallOption) (*Empty, error) {
+	if m.NoReturnFunc == nil {
+		return nil, client.ErrNotMocked
+	}
+
+	return m.NoReturnFunc(ctx, in, opts...)
+}
+
+func (m *MockExampleServiceGSClient) ServerStream(ctx context.Context, in *Request, opts ...client.CallOption) (ExampleService_ServerStreamGSClient, error) {
+	if m.ServerStreamFunc == nil {
+		return nil, client.ErrNotMocked
+	}
+
+	return m.ServerStreamFunc(ctx, in, opts...)
+}
+
+func (m *MockExampleServiceGSClient) ClientStream(ctx context.Context, opts ...client.CallOption) (ExampleService_ClientStreamGSClient, error) {
+	if m.ClientStreamFunc == nil {
+		return nil, client.ErrNotMocked
+	}
+
+	return m.ClientStreamFunc(ctx, opts...)
+}
+
+func (m *MockExampleServiceGSClient) BiDirectionalStream(ctx context.Context, opts ...client.CallOption) (ExampleService_BiDirectionalStreamGSClient, error) {
+	if m.BiDirectionalStreamFunc == nil {
+		return nil, client.ErrNotMocked
+	}
+
+	return m.BiDirectionalStreamFunc(ctx, opts...)
+}
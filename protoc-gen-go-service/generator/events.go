@@ -0,0 +1,85 @@
+package generator
+
+import (
+	"path"
+
+	"github.com/easeq/go-service/protoc-gen-go-service/options"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+)
+
+// Event holds the message published on a broker topic
+type Event struct {
+	// Name is the suffix of the generated Publish and Subscribe functions
+	Name    string
+	Topic   string
+	Message *protogen.Message
+	// GoType is the Go type of the message, qualified if it's defined in another package
+	GoType string
+}
+
+// Events returns the events of the file, the messages with the event option
+// and the method inputs of the services with the events option.
+// The packages of the method inputs defined in other packages are added to the packages to import.
+func Events(gen *protogen.Plugin, f *protogen.File, packages map[protogen.GoImportPath]protogen.GoPackageName) []*Event {
+	events := []*Event{}
+	for _, message := range messages(f.Messages) {
+		event, ok := proto.GetExtension(message.Desc.Options(), options.E_Event).(*options.Event)
+		if !ok || event.GetTopic() == "" {
+			continue
+		}
+
+		events = append(events, &Event{
+			Name:    message.GoIdent.GoName,
+			Topic:   event.GetTopic(),
+			Message: message,
+			GoType:  message.GoIdent.GoName,
+		})
+	}
+
+	for _, service := range f.Services {
+		event, ok := proto.GetExtension(service.Desc.Options(), options.E_Events).(*options.Event)
+		if !ok || event.GetTopic() == "" {
+			continue
+		}
+
+		for _, method := range service.Methods {
+			events = append(events, &Event{
+				Name:    service.GoName + method.GoName,
+				Topic:   event.GetTopic() + "." + method.GoName,
+				Message: method.Input,
+				GoType:  goType(gen, f, method.Input, packages),
+			})
+		}
+	}
+
+	return events
+}
+
+// goType returns the Go type of the message used in the file,
+// qualified with the name of its package if it's defined in another package
+func goType(gen *protogen.Plugin, f *protogen.File, message *protogen.Message, packages map[protogen.GoImportPath]protogen.GoPackageName) string {
+	ident := message.GoIdent
+	if ident.GoImportPath == f.GoImportPath {
+		return ident.GoName
+	}
+
+	pkg := protogen.GoPackageName(path.Base(string(ident.GoImportPath)))
+	if file, ok := gen.FilesByPath[message.Desc.ParentFile().Path()]; ok {
+		pkg = file.GoPackageName
+	}
+
+	packages[ident.GoImportPath] = pkg
+	return string(pkg) + "." + ident.GoName
+}
+
+// messages returns the messages and their nested messages
+func messages(ms []*protogen.Message) []*protogen.Message {
+	all := []*protogen.Message{}
+	for _, m := range ms {
+		all = append(all, m)
+		all = append(all, messages(m.Messages)...)
+	}
+
+	return all
+}
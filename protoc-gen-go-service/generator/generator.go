@@ -2,11 +2,14 @@ package generator
 
 import (
 	"bytes"
-	"html/template"
+	"fmt"
+	"go/format"
+	"text/template"
 
 	"github.com/easeq/go-service/protoc-gen-go-service/options"
 	"github.com/iancoleman/strcase"
 	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/types/descriptorpb"
 )
 
 // Generator holds the config used to generate the files
@@ -19,30 +22,92 @@ type Generator struct {
 	RegistryTags   map[string]*options.RegistryTag
 	Services       []*protogen.Service
 	Imports        map[string]bool
+	Events         []*Event
+	// Packages are the named imports of the messages defined in other packages
+	Packages map[protogen.GoImportPath]protogen.GoPackageName
+	// Gateway generates the grpc-gateway handler registrars of the services
+	Gateway bool
+	// Unimplemented generates the unimplemented bases of the services
+	Unimplemented bool
+	// Mocks generates the mocks of the service clients
+	Mocks bool
+	// TagTemplates are the registry tag templates by target, TagTemplates by default
+	TagTemplates map[string]string
 }
 
 // GenerateFile generates a _ascii.pb.go file containing gRPC service definitions.
-func (g *Generator) GenerateFile() *protogen.GeneratedFile {
-	filename := g.FilenamePrefix + ".pb.gs.go"
-	gf := g.Gen.NewGeneratedFile(filename, g.GoImportPath)
+// It returns an error if the template fails, e.g. for an invalid option.
+func (g *Generator) GenerateFile() (*protogen.GeneratedFile, error) {
+	// os is imported only if the registry tags reference env vars
+	for service, tag := range g.RegistryTags {
+		if tag == nil {
+			continue
+		}
+
+		tags, err := g.ServiceRegistryTags(service, tag)
+		if err != nil {
+			return nil, err
+		}
 
-	t, err := template.New("gs.tmpl").Funcs(template.FuncMap{
+		if UsesEnv(tags) {
+			g.Imports["os"] = true
+		}
+	}
+
+	return g.generate(g.FilenamePrefix+".pb.gs.go", "gs.tmpl", tmpl)
+}
+
+// GenerateMocks generates a .pb.gs.mock.go file containing the mocks of the service clients
+func (g *Generator) GenerateMocks() (*protogen.GeneratedFile, error) {
+	return g.generate(g.FilenamePrefix+".pb.gs.mock.go", "mock.tmpl", mockTmpl)
+}
+
+// generate executes the template and writes the result to the file
+func (g *Generator) generate(filename string, name string, text string) (*protogen.GeneratedFile, error) {
+	t, err := template.New(name).Funcs(template.FuncMap{
 		"camelCase": strcase.ToLowerCamel,
 		"add": func(x, y int) int {
 			x = x + y
 			return x
 		},
-	}).Parse(tmpl)
+		"idempotent":   Idempotent,
+		"policy":       Policy,
+		"registryTags": g.ServiceRegistryTags,
+		"tagExpr":      RegistryTagExpr,
+	}).Parse(text)
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("error parsing template %s: %v", name, err)
 	}
 
 	var result bytes.Buffer
 	if err := t.Execute(&result, g); err != nil {
-		panic(err)
+		return nil, fmt.Errorf("error generating %s: %v", filename, err)
 	}
 
-	gf.P(result.String())
+	// The imports added by the template and the generator are sorted as gofmt does
+	content, err := format.Source(result.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("error formatting %s: %v", filename, err)
+	}
+
+	gf := g.Gen.NewGeneratedFile(filename, g.GoImportPath)
+	gf.P(string(content))
+
+	return gf, nil
+}
+
+// Idempotent returns true if the method is marked as idempotent,
+// or as free of side effects, using the idempotency_level method option
+func Idempotent(method *protogen.Method) bool {
+	opts, ok := method.Desc.Options().(*descriptorpb.MethodOptions)
+	if !ok {
+		return false
+	}
 
-	return gf
+	switch opts.GetIdempotencyLevel() {
+	case descriptorpb.MethodOptions_NO_SIDE_EFFECTS, descriptorpb.MethodOptions_IDEMPOTENT:
+		return true
+	default:
+		return false
+	}
 }
@@ -0,0 +1,49 @@
+package generator
+
+var mockTmpl = `
+// Code generated by protoc-gen-go_service. DO NOT EDIT.
+// versions:
+//	protoc	v{{.Gen.Request.CompilerVersion.Major}}.{{.Gen.Request.CompilerVersion.Minor}}.{{.Gen.Request.CompilerVersion.Patch}}
+
+package {{.GoPackageName}}
+
+import (
+	"context"
+
+	"github.com/easeq/go-service/client"
+)
+
+{{range .Services}}
+{{$serviceName := .GoName}}
+// Mock{{$serviceName}}GSClient is a mock of {{$serviceName}}GSClient for unit tests.
+// The calls are passed to the functions set, the methods without one return client.ErrNotMocked.
+type Mock{{$serviceName}}GSClient struct {
+	{{- range .Methods}}
+	{{.GoName}}Func func({{template "params" .}}) {{template "results" .}}
+	{{- end}}
+}
+
+var _ {{$serviceName}}GSClient = (*Mock{{$serviceName}}GSClient)(nil)
+{{range .Methods}}
+func (m *Mock{{$serviceName}}GSClient) {{.GoName}}({{template "params" .}}) {{template "results" .}} {
+	if m.{{.GoName}}Func == nil {
+		return nil, client.ErrNotMocked
+	}
+
+	return m.{{.GoName}}Func(ctx, {{- if not .Desc.IsStreamingClient}} in,{{end}} opts...)
+}
+{{end}}
+{{end}}
+
+{{- define "params" -}}
+ctx context.Context, {{- if not .Desc.IsStreamingClient}} in *{{.Input.GoIdent.GoName}},{{end}} opts ...client.CallOption
+{{- end}}
+
+{{- define "results" -}}
+{{if or .Desc.IsStreamingServer .Desc.IsStreamingClient -}}
+({{.Parent.GoName}}_{{.GoName}}GSClient, error)
+{{- else -}}
+(*{{.Output.GoIdent.GoName}}, error)
+{{- end}}
+{{- end}}
+`
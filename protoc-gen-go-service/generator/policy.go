@@ -0,0 +1,127 @@
+package generator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/easeq/go-service/protoc-gen-go-service/options"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// statusCodes are the names of the gRPC status codes accepted as retryable codes
+var statusCodes = map[string]bool{
+	"OK":                  true,
+	"CANCELLED":           true,
+	"UNKNOWN":             true,
+	"INVALID_ARGUMENT":    true,
+	"DEADLINE_EXCEEDED":   true,
+	"NOT_FOUND":           true,
+	"ALREADY_EXISTS":      true,
+	"PERMISSION_DENIED":   true,
+	"RESOURCE_EXHAUSTED":  true,
+	"FAILED_PRECONDITION": true,
+	"ABORTED":             true,
+	"OUT_OF_RANGE":        true,
+	"UNIMPLEMENTED":       true,
+	"INTERNAL":            true,
+	"UNAVAILABLE":         true,
+	"DATA_LOSS":           true,
+	"UNAUTHENTICATED":     true,
+}
+
+// CallPolicy holds the options of the calls to a method,
+// from its idempotency_level and method_policy options
+type CallPolicy struct {
+	// Idempotent marks the calls safe to retry or hedge
+	Idempotent bool
+	// Timeout is the Go expression of the deadline of the calls, e.g. "5 * time.Second"
+	Timeout string
+	// MaxAttempts is the maximum number of attempts of the calls
+	MaxAttempts int32
+	// RetryableCodes are the names of the status codes retried
+	RetryableCodes []string
+	// AuthRequired rejects the calls without credentials
+	AuthRequired bool
+}
+
+// HasCallOptions returns true if the client calls need options
+func (p *CallPolicy) HasCallOptions() bool {
+	return p.Idempotent || p.Timeout != "" || p.MaxAttempts > 0 || len(p.RetryableCodes) > 0
+}
+
+// HasServerOptions returns true if the server enforces options on the calls
+func (p *CallPolicy) HasServerOptions() bool {
+	return p.Timeout != "" || p.AuthRequired
+}
+
+// MethodPolicy returns the method_policy option of the method, or nil if it isn't set
+func MethodPolicy(method *protogen.Method) *options.MethodPolicy {
+	opts, ok := method.Desc.Options().(*descriptorpb.MethodOptions)
+	if !ok || opts == nil {
+		return nil
+	}
+
+	policy, ok := proto.GetExtension(opts, options.E_MethodPolicy).(*options.MethodPolicy)
+	if !ok {
+		return nil
+	}
+
+	return policy
+}
+
+// Policy returns the call policy of the method.
+// It returns an error for an invalid timeout or retryable code.
+func Policy(method *protogen.Method) (*CallPolicy, error) {
+	p := &CallPolicy{Idempotent: Idempotent(method)}
+
+	mp := MethodPolicy(method)
+	if mp == nil {
+		return p, nil
+	}
+
+	p.Idempotent = p.Idempotent || mp.GetIdempotent()
+	p.MaxAttempts = mp.GetMaxAttempts()
+	p.AuthRequired = mp.GetAuthRequired()
+
+	if timeout := mp.GetTimeout(); timeout != "" {
+		d, err := time.ParseDuration(timeout)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("%s: invalid timeout %q", method.Desc.FullName(), timeout)
+		}
+
+		p.Timeout = durationExpr(d)
+	}
+
+	for _, code := range mp.GetRetryableCodes() {
+		if !statusCodes[code] {
+			return nil, fmt.Errorf("%s: invalid retryable code %q", method.Desc.FullName(), code)
+		}
+
+		p.RetryableCodes = append(p.RetryableCodes, code)
+	}
+
+	return p, nil
+}
+
+// durationExpr returns the Go expression of the duration, using the largest unit dividing it
+func durationExpr(d time.Duration) string {
+	units := []struct {
+		unit time.Duration
+		name string
+	}{
+		{time.Hour, "time.Hour"},
+		{time.Minute, "time.Minute"},
+		{time.Second, "time.Second"},
+		{time.Millisecond, "time.Millisecond"},
+	}
+
+	for _, u := range units {
+		if d%u.unit == 0 {
+			return fmt.Sprintf("%d * %s", d/u.unit, u.name)
+		}
+	}
+
+	return fmt.Sprintf("time.Duration(%d)", d)
+}
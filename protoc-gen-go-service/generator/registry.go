@@ -0,0 +1,203 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/easeq/go-service/protoc-gen-go-service/options"
+)
+
+const (
+	// TARGET_TRAEFIK generates the traefik router and middleware labels
+	TARGET_TRAEFIK = "traefik"
+	// TARGET_CONSUL_CONNECT generates the Consul Connect metadata of the upstream
+	TARGET_CONSUL_CONNECT = "consul-connect"
+	// TARGET_ENVOY generates the Envoy route metadata
+	TARGET_ENVOY = "envoy"
+	// TARGET_KUBERNETES generates the Kubernetes ingress annotations
+	TARGET_KUBERNETES = "kubernetes"
+	// DEFAULT_PROVIDER is the traefik provider of the generated middlewares
+	DEFAULT_PROVIDER = "consulcatalog"
+
+	// envMarker delimits the env var names in the rendered tags
+	envMarker = "\x00"
+)
+
+// TagTemplates are the templates of the registry tags by target.
+// Each line rendered is a tag, the empty lines are ignored.
+// The env vars referenced with the env func, e.g. {{env .Tag.Host}}, are read with os.Getenv
+// when the tags are added to the server, the rest of the text is kept as is.
+var TagTemplates = map[string]string{
+	TARGET_TRAEFIK: `
+traefik.http.routers.{{.Tag.Name}}.rule=Host(` + "`" + `{{env .Tag.Host}}` + "`" + `) && PathPrefix(` + "`" + `{{.Tag.Path}}` + "`" + `)
+{{- if .Tag.Entrypoints}}
+traefik.http.routers.{{.Tag.Name}}.entrypoints={{join .Tag.Entrypoints ","}}
+{{- end}}
+{{- if .Tag.Stripprefix}}
+traefik.http.middlewares.{{.Tag.Name}}-stripprefix.stripprefix.prefixes={{.Tag.Stripprefix}}
+{{- end}}
+{{- with middlewares .Tag}}
+traefik.http.routers.{{$.Tag.Name}}.middlewares={{join . ","}}
+{{- end}}
+traefik.enable=true
+`,
+	TARGET_CONSUL_CONNECT: `
+connect.service={{.Tag.Name}}
+connect.host={{env .Tag.Host}}
+connect.path={{.Tag.Path}}
+{{- if .Tag.Stripprefix}}
+connect.stripprefix={{.Tag.Stripprefix}}
+{{- end}}
+`,
+	TARGET_ENVOY: `
+envoy.cluster={{.Tag.Name}}
+envoy.domain={{env .Tag.Host}}
+envoy.prefix={{.Tag.Path}}
+{{- if .Tag.Stripprefix}}
+envoy.prefix_rewrite={{strip .Tag.Path .Tag.Stripprefix}}
+{{- end}}
+`,
+	TARGET_KUBERNETES: `
+app.kubernetes.io/name={{.Tag.Name}}
+ingress.kubernetes.io/host={{env .Tag.Host}}
+ingress.kubernetes.io/path={{.Tag.Path}}
+{{- if .Tag.Stripprefix}}
+ingress.kubernetes.io/rewrite-target={{strip .Tag.Path .Tag.Stripprefix}}
+{{- end}}
+`,
+}
+
+// TagData is passed to the registry tag templates
+type TagData struct {
+	// Service is the Go name of the service
+	Service string
+	// Tag is the registry_tag option of the service
+	Tag *options.RegistryTag
+}
+
+var tagFuncs = template.FuncMap{
+	"join": strings.Join,
+	// env returns the reference to the env var, read when the tags are added
+	"env": func(key string) string {
+		return envMarker + key + envMarker
+	},
+	// strip returns the path without the prefix, or the root path
+	"strip": func(path, prefix string) string {
+		if path = strings.TrimPrefix(path, prefix); path == "" || path[0] != '/' {
+			path = "/" + path
+		}
+
+		return path
+	},
+	// middlewares returns the traefik middlewares of the router, including the generated ones
+	"middlewares": func(tag *options.RegistryTag) []string {
+		middlewares := []string{}
+		if tag.GetStripprefix() != "" {
+			provider := tag.GetProvider()
+			if provider == "" {
+				provider = DEFAULT_PROVIDER
+			}
+
+			middlewares = append(middlewares, tag.GetName()+"-stripprefix@"+provider)
+		}
+
+		return append(middlewares, tag.GetMiddlewares()...)
+	},
+}
+
+// LoadTagTemplates adds the templates read from the files to the registry tag templates,
+// using the parameters formatted as <target>:<path>.
+// A template with the name of a built-in target replaces it.
+func LoadTagTemplates(templates map[string]string, params []string) error {
+	for _, param := range params {
+		parts := strings.SplitN(param, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("invalid registry tag template %q, expected <target>:<path>", param)
+		}
+
+		target, path := parts[0], parts[1]
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("error reading registry tag template %s: %v", target, err)
+		}
+
+		templates[target] = string(b)
+	}
+
+	return nil
+}
+
+// UsesEnv returns true if one of the rendered tags references an env var
+func UsesEnv(tags []string) bool {
+	for _, tag := range tags {
+		if strings.Contains(tag, envMarker) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RegistryTagExpr returns the Go expression of the rendered tag,
+// concatenating the quoted text with os.Getenv calls for the env vars referenced
+func RegistryTagExpr(tag string) string {
+	parts := strings.Split(tag, envMarker)
+	exprs := make([]string, 0, len(parts))
+	for i, part := range parts {
+		switch {
+		case i%2 == 1:
+			exprs = append(exprs, "os.Getenv("+strconv.Quote(part)+")")
+		case part != "":
+			exprs = append(exprs, strconv.Quote(part))
+		}
+	}
+
+	if len(exprs) == 0 {
+		return `""`
+	}
+
+	return strings.Join(exprs, " + ")
+}
+
+// ServiceRegistryTags returns the registry tags of the service for each target of the tag, traefik by default
+func (g *Generator) ServiceRegistryTags(service string, tag *options.RegistryTag) ([]string, error) {
+	templates := g.TagTemplates
+	if templates == nil {
+		templates = TagTemplates
+	}
+
+	targets := tag.GetTargets()
+	if len(targets) == 0 {
+		targets = []string{TARGET_TRAEFIK}
+	}
+
+	tags := []string{}
+	for _, target := range targets {
+		text, ok := templates[target]
+		if !ok {
+			return nil, fmt.Errorf("%s: unknown registry tag target %q", service, target)
+		}
+
+		t, err := template.New(target).Funcs(tagFuncs).Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing registry tag template %s: %v", target, err)
+		}
+
+		var result bytes.Buffer
+		if err := t.Execute(&result, TagData{Service: service, Tag: tag}); err != nil {
+			return nil, fmt.Errorf("error executing registry tag template %s: %v", target, err)
+		}
+
+		for _, line := range strings.Split(result.String(), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				tags = append(tags, line)
+			}
+		}
+	}
+
+	return tags, nil
+}
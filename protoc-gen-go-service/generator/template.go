@@ -9,11 +9,23 @@ package {{.GoPackageName}}
 
 import (
 	"context"
-
+	{{if .Services}}
 	"github.com/easeq/go-service/client"
+	grpc_server "github.com/easeq/go-service/server/grpc"
+	{{- if .Gateway}}
+	"github.com/easeq/go-service/server/gateway"
+	"google.golang.org/grpc"
+	{{- end}}
+	{{- end}}
+	{{- if .Events}}
+	"github.com/easeq/go-service/broker"
+	{{- end}}
 	{{range $import, $val := .Imports -}}
 	"{{$import}}"
 	{{end}}
+	{{- range $path, $name := .Packages}}
+	{{$name}} {{$path}}
+	{{- end}}
 )
 
 {{$pkg := .GoPackageName}}
@@ -27,8 +39,10 @@ type {{$serviceName}}GSClient interface {
 	{{range .Methods -}}
 	{{if and (not .Desc.IsStreamingServer) (not .Desc.IsStreamingClient) -}}
 	{{.GoName}}(ctx context.Context, in *{{.Input.GoIdent.GoName}}, opts ...client.CallOption) (*{{.Output.GoIdent.GoName}}, error)
+	{{else if not .Desc.IsStreamingClient -}}
+	{{.GoName}}(ctx context.Context, in *{{.Input.GoIdent.GoName}}, opts ...client.CallOption) ({{$serviceName}}_{{.GoName}}GSClient, error)
 	{{else -}}
-	{{.GoName}}(ctx context.Context, in *{{.Input.GoIdent.GoName}}, opts ...client.CallOption) (client.StreamClient, error)
+	{{.GoName}}(ctx context.Context, opts ...client.CallOption) ({{$serviceName}}_{{.GoName}}GSClient, error)
 	{{end}}
 	{{- end}}
 }
@@ -58,6 +72,7 @@ func (sc *{{$serviceNameCamel}}GSClient) GetDialOptions() []client.DialOption {
 {{$outputName := .Output.GoIdent.GoName}}
 {{if and (not .Desc.IsStreamingServer) (not .Desc.IsStreamingClient)}}
 func (sc *{{$serviceNameCamel}}GSClient) {{$methodName}}(ctx context.Context, in *{{$inputName}}, opts ...client.CallOption) (*{{$outputName}}, error) {
+	{{- template "callOptions" .}}
 	res := new({{$outputName}})
 	err := sc.Call(ctx, sc, "/{{$serviceFullName}}/{{$methodName}}", in, res, opts...)
 	if err != nil {
@@ -67,35 +82,167 @@ func (sc *{{$serviceNameCamel}}GSClient) {{$methodName}}(ctx context.Context, in
 	return res, nil
 }
 {{else}}
-func (sc *{{$serviceNameCamel}}GSClient) {{$methodName}}(ctx context.Context, in *{{$inputName}}, opts ...client.CallOption) (client.StreamClient, error) {
+{{$streamName := printf "%s_%sGSClient" $serviceName $methodName}}
+{{$streamNameCamel := printf "%s%sGSClient" $serviceNameCamel $methodName}}
+{{if not .Desc.IsStreamingClient}}
+func (sc *{{$serviceNameCamel}}GSClient) {{$methodName}}(ctx context.Context, in *{{$inputName}}, opts ...client.CallOption) ({{$streamName}}, error) {
+	{{- template "callOptions" .}}
 	stream, err := sc.Stream(ctx, sc, &{{$serviceName}}_ServiceDesc.Streams[{{index $streams (printf "%s%s" .Parent.GoName .GoName)}}], "/{{$serviceFullName}}/{{$methodName}}", in, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	return stream, nil
+	return &{{$streamNameCamel}}{stream}, nil
+}
+{{else}}
+func (sc *{{$serviceNameCamel}}GSClient) {{$methodName}}(ctx context.Context, opts ...client.CallOption) ({{$streamName}}, error) {
+	{{- template "callOptions" .}}
+	stream, err := sc.Stream(ctx, sc, &{{$serviceName}}_ServiceDesc.Streams[{{index $streams (printf "%s%s" .Parent.GoName .GoName)}}], "/{{$serviceFullName}}/{{$methodName}}", nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &{{$streamNameCamel}}{stream}, nil
+}
+{{end}}
+
+type {{$streamName}} interface {
+	{{- if .Desc.IsStreamingClient}}
+	Send(*{{$inputName}}) error
+	{{- end}}
+	{{- if .Desc.IsStreamingServer}}
+	Recv() (*{{$outputName}}, error)
+	{{- else}}
+	CloseAndRecv() (*{{$outputName}}, error)
+	{{- end}}
+	client.Stream
+}
+
+type {{$streamNameCamel}} struct {
+	client.StreamClient
+}
+{{if .Desc.IsStreamingClient}}
+func (x *{{$streamNameCamel}}) Send(m *{{$inputName}}) error {
+	return x.StreamClient.Send(m)
+}
+{{end}}
+{{- if .Desc.IsStreamingServer}}
+func (x *{{$streamNameCamel}}) Recv() (*{{$outputName}}, error) {
+	m := new({{$outputName}})
+	if err := x.StreamClient.Recv(m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+{{else}}
+func (x *{{$streamNameCamel}}) CloseAndRecv() (*{{$outputName}}, error) {
+	m := new({{$outputName}})
+	if err := x.StreamClient.CloseAndRecv(m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
 }
 {{end}}
 {{end}}
 {{end}}
 
+// Register{{$serviceName}}GSServer registers the service implementation on the gRPC server
+func Register{{$serviceName}}GSServer(s *grpc_server.Grpc, srv {{$serviceName}}Server) {
+	Register{{$serviceName}}Server(s.Server, srv)
+	{{- range .Methods}}
+	{{- $methodName := .GoName}}
+	{{- with policy .}}{{if .HasServerOptions}}
+	s.SetMethodOptions("/{{$serviceFullName}}/{{$methodName}}", grpc_server.MethodOptions{
+		{{- if .Timeout}}
+		Timeout: {{.Timeout}},
+		{{- end}}
+		{{- if .AuthRequired}}
+		AuthRequired: true,
+		{{- end}}
+	})
+	{{- end}}{{end}}
+	{{- end}}
+}
+
+// New{{$serviceName}}GSServer creates the gRPC server with the service implementation registered
+func New{{$serviceName}}GSServer(srv {{$serviceName}}Server, opts ...grpc_server.Option) *grpc_server.Grpc {
+	s := grpc_server.NewGrpc(opts...)
+	Register{{$serviceName}}GSServer(s, srv)
+
+	return s
+}
+{{if $.Gateway}}
+// New{{$serviceName}}HTTPServiceHandlerRegistrar returns the gateway registrar,
+// proxying the HTTP requests to the gRPC server at the endpoint
+func New{{$serviceName}}HTTPServiceHandlerRegistrar(endpoint string, opts ...grpc.DialOption) gateway.HTTPServiceHandlerRegistrar {
+	return func(ctx context.Context, g *gateway.Gateway) error {
+		return Register{{$serviceName}}HandlerFromEndpoint(ctx, g.Mux, endpoint, opts)
+	}
+}
+{{end}}
+{{- if $.Unimplemented}}
+// Unimplemented{{$serviceName}}GSServer is the base of the service implementations,
+// the methods not implemented return codes.Unimplemented
+type Unimplemented{{$serviceName}}GSServer struct {
+	Unimplemented{{$serviceName}}Server
+}
+{{end}}
+{{end}}
+
+{{range .Events}}
+{{$messageName := .Message.GoIdent.GoName}}
+// {{.Name}}Topic is the topic of the {{$messageName}} events
+const {{.Name}}Topic = "{{.Topic}}"
+
+// Publish{{.Name}} publishes the {{$messageName}} event encoded with protobuf
+func Publish{{.Name}}(ctx context.Context, b broker.Broker, m *{{.GoType}}, opts ...broker.PublishOption) error {
+	return b.Publish(ctx, {{.Name}}Topic, broker.Proto(m), opts...)
+}
+
+// Subscribe{{.Name}} subscribes the handler to the {{$messageName}} events
+func Subscribe{{.Name}}(ctx context.Context, b broker.Broker, handler func(context.Context, *{{.GoType}}) error, opts ...broker.SubscribeOption) error {
+	return b.Subscribe(ctx, {{.Name}}Topic, broker.HandlerFunc(func(ctx context.Context, msg *broker.Message) error {
+		m := new({{.GoType}})
+		if err := broker.UnmarshalProto(msg, m); err != nil {
+			return err
+		}
+
+		return handler(ctx, m)
+	}), opts...)
+}
+{{end}}
+
 {{range $serviceName, $tag := .RegistryTags -}}
 {{if $tag}}
 func Add{{$serviceName}}ServerRegistryTags(server server.Server) {
 	tags := []string{
-		"traefik.http.routers.{{$tag.Name}}.rule=Host(` + "`" + `" + os.Getenv("{{$tag.Host}}") + "` + "`" + `) && PathPrefix(` + "`" + `{{$tag.Path}}` + "`" + `)",
-		{{if $tag.Stripprefix -}}
-		"traefik.http.middlewares.{{$tag.Name}}-stripprefix.stripprefix.prefixes={{$tag.Stripprefix}}",
-		"traefik.http.routers.{{$tag.Name}}.middlewares={{$tag.Name}}-stripprefix@consulcatalog",
+		{{- range registryTags $serviceName $tag}}
+		{{tagExpr .}},
 		{{- end}}
 	}
 
-	if len(tags) > 0 {
-		tags = append(tags, "traefik.enable=true")
-	}
-
 	server.AddRegistryTags(tags...)
 }
 {{- end}}
 {{- end}}
+{{define "callOptions"}}
+{{- with policy .}}{{if .HasCallOptions}}
+	opts = append([]client.CallOption{
+		{{- if .Idempotent}}
+		client.Idempotent(),
+		{{- end}}
+		{{- if .Timeout}}
+		client.WithTimeout({{.Timeout}}),
+		{{- end}}
+		{{- if .MaxAttempts}}
+		client.WithMaxAttempts({{.MaxAttempts}}),
+		{{- end}}
+		{{- if .RetryableCodes}}
+		client.WithRetryableCodes({{range $i, $code := .RetryableCodes}}{{if $i}}, {{end}}"{{$code}}"{{end}}),
+		{{- end}}
+	}, opts...)
+{{- end}}{{end}}
+{{- end}}
 `
@@ -1,31 +1,72 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"strings"
 
 	// "github.com/easeq/go-service/cmd/protoc-gen-go-service/options"
 	gs_generator "github.com/easeq/go-service/protoc-gen-go-service/generator"
+	"github.com/easeq/go-service/protoc-gen-go-service/openapi"
 	"github.com/easeq/go-service/protoc-gen-go-service/options"
 	"google.golang.org/protobuf/compiler/protogen"
 	"google.golang.org/protobuf/proto"
 )
 
+var (
+	flags         flag.FlagSet
+	gateway       = flags.Bool("gateway", false, "generate the grpc-gateway handler registrars")
+	unimplemented = flags.Bool("unimplemented", false, "generate the unimplemented server bases")
+	mocks         = flags.Bool("mocks", false, "generate the mocks of the service clients")
+	openAPI       = flags.Bool("openapi", false, "generate the OpenAPI documents of the services")
+	apiVersion    = flags.String("openapi_version", "1.0.0", "the version of the API in the OpenAPI documents")
+	_             = flags.Bool("logtostderr", false, "ignored")
+	tagTemplates  = templateFlags{}
+)
+
+// templateFlags holds the registry_tags parameters, which can be passed several times
+type templateFlags []string
+
+func (f *templateFlags) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *templateFlags) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+func init() {
+	flags.Var(&tagTemplates, "registry_tags", "add a registry tag template as <target>:<path>")
+}
+
 func main() {
-	protogen.Options{}.Run(run)
+	protogen.Options{ParamFunc: flags.Set}.Run(run)
 }
 
 func run(gen *protogen.Plugin) error {
+	templates := map[string]string{}
+	for target, text := range gs_generator.TagTemplates {
+		templates[target] = text
+	}
+
+	if err := gs_generator.LoadTagTemplates(templates, tagTemplates); err != nil {
+		return err
+	}
+
 	for _, f := range gen.Files {
-		if !f.Generate || len(f.Services) == 0 {
+		if !f.Generate {
 			continue
 		}
 
-		skip := true
+		packages := make(map[protogen.GoImportPath]protogen.GoPackageName)
+		events := gs_generator.Events(gen, f, packages)
+		skip := len(events) == 0
 		ss := make(map[string]int)
 		registryTags := map[string]*options.RegistryTag{}
 		imports := make(map[string]bool)
 		for _, service := range f.Services {
-			// Skip file generation if there are no methods
+			// Skip file generation if there are neither methods nor events
 			if len(service.Methods) != 0 {
 				skip = false
 			}
@@ -37,6 +78,11 @@ func run(gen *protogen.Plugin) error {
 					ss[fmt.Sprintf("%s%s", service.GoName, method.GoName)] = index
 					index++
 				}
+
+				// The method timeouts are generated as time.Duration
+				if gs_generator.MethodPolicy(method).GetTimeout() != "" {
+					imports["time"] = true
+				}
 			}
 
 			// Get service registry tags for traefik
@@ -48,11 +94,10 @@ func run(gen *protogen.Plugin) error {
 			tags := proto.GetExtension(opts, options.E_RegistryTag)
 			registryTag, ok := tags.(*options.RegistryTag)
 			if !ok {
-				panic("Invalid registry tags")
+				return fmt.Errorf("%s: invalid registry tags", service.Desc.FullName())
 			}
 
 			registryTags[service.GoName] = registryTag
-			imports["os"] = true
 			imports["github.com/easeq/go-service/server"] = true
 		}
 
@@ -69,8 +114,29 @@ func run(gen *protogen.Plugin) error {
 			RegistryTags:   registryTags,
 			Services:       f.Services,
 			Imports:        imports,
+			Events:         events,
+			Packages:       packages,
+			Gateway:        *gateway,
+			Unimplemented:  *unimplemented,
+			Mocks:          *mocks,
+			TagTemplates:   templates,
+		}
+
+		if _, err := generator.GenerateFile(); err != nil {
+			return err
+		}
+
+		if generator.Mocks && len(f.Services) > 0 {
+			if _, err := generator.GenerateMocks(); err != nil {
+				return err
+			}
+		}
+
+		if *openAPI && len(f.Services) > 0 {
+			if _, err := openapi.GenerateFile(gen, f, *apiVersion); err != nil {
+				return err
+			}
 		}
-		generator.GenerateFile()
 	}
 	return nil
 }
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/easeq/go-service/protoc-gen-go-service/options"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// The descriptors of example/*.proto, regenerated with make gen-protoset
+const protoset = "testdata/example.protoset"
+
+// The parameters the example files are generated with, see make gen
+const params = "logtostderr=true,mocks=true,openapi=true"
+
+var update = flag.Bool("update", false, "update the golden files in example/")
+
+// loadFiles returns the descriptors of the example protos and their dependencies
+func loadFiles(t *testing.T) []*descriptorpb.FileDescriptorProto {
+	b, err := os.ReadFile(protoset)
+	if err != nil {
+		t.Fatalf("reading %s: %v", protoset, err)
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(b, set); err != nil {
+		t.Fatalf("unmarshalling %s: %v", protoset, err)
+	}
+
+	return set.File
+}
+
+// generate runs the plugin over the example protos and returns its response
+func generate(t *testing.T, files []*descriptorpb.FileDescriptorProto, parameter string) *pluginpb.CodeGeneratorResponse {
+	// The flags are global, reset them between the runs
+	*gateway, *unimplemented, *mocks, *openAPI = false, false, false, false
+	*apiVersion = "1.0.0"
+	tagTemplates = templateFlags{}
+
+	gen, err := protogen.Options{ParamFunc: flags.Set}.New(&pluginpb.CodeGeneratorRequest{
+		FileToGenerate:  []string{"example/example.proto", "example/noservice.proto"},
+		Parameter:       proto.String(parameter),
+		ProtoFile:       files,
+		CompilerVersion: &pluginpb.Version{Major: proto.Int32(3), Minor: proto.Int32(12), Patch: proto.Int32(3)},
+	})
+	if err != nil {
+		t.Fatalf("creating plugin: %v", err)
+	}
+
+	if err := run(gen); err != nil {
+		gen.Error(err)
+	}
+
+	return gen.Response()
+}
+
+// findFile returns the descriptor of the file
+func findFile(files []*descriptorpb.FileDescriptorProto, name string) *descriptorpb.FileDescriptorProto {
+	for _, f := range files {
+		if f.GetName() == name {
+			return f
+		}
+	}
+
+	return nil
+}
+
+func TestGolden(t *testing.T) {
+	res := generate(t, loadFiles(t), params)
+	if res.Error != nil {
+		t.Fatalf("generating files: %s", res.GetError())
+	}
+
+	generated := map[string]bool{}
+	for _, f := range res.File {
+		golden := filepath.Join("example", f.GetName())
+		generated[golden] = true
+
+		// The generated Go files are gofmt-clean
+		if filepath.Ext(golden) == ".go" {
+			formatted, err := format.Source([]byte(f.GetContent()))
+			if err != nil {
+				t.Fatalf("formatting %s: %v", golden, err)
+			}
+
+			if !bytes.Equal(formatted, []byte(f.GetContent())) {
+				t.Errorf("%s isn't formatted with gofmt", golden)
+			}
+		}
+
+		if *update {
+			if err := os.WriteFile(golden, []byte(f.GetContent()), 0644); err != nil {
+				t.Fatalf("updating %s: %v", golden, err)
+			}
+
+			continue
+		}
+
+		want, err := os.ReadFile(golden)
+		if err != nil {
+			t.Fatalf("reading %s: %v", golden, err)
+		}
+
+		if !bytes.Equal(want, []byte(f.GetContent())) {
+			t.Errorf("%s differs from the generated file, run go test -update to update it:\n%s", golden, f.GetContent())
+		}
+	}
+
+	for _, golden := range []string{"example/example.pb.gs.go", "example/example.pb.gs.mock.go", "example/example.openapi.json"} {
+		if !generated[golden] {
+			t.Errorf("%s not generated", golden)
+		}
+	}
+
+	// The files without services nor events are skipped
+	if generated["example/noservice.pb.gs.go"] {
+		t.Errorf("example/noservice.pb.gs.go generated")
+	}
+}
+
+func TestPluginErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  string
+		set     func(svc *descriptorpb.ServiceDescriptorProto)
+		wantErr string
+	}{
+		{
+			name: "unknown registry tag target",
+			set: func(svc *descriptorpb.ServiceDescriptorProto) {
+				proto.SetExtension(svc.Options, options.E_RegistryTag, &options.RegistryTag{Name: "svc", Targets: []string{"unknown"}})
+			},
+			wantErr: `unknown registry tag target "unknown"`,
+		},
+		{
+			name: "invalid registry tag template",
+			set: func(svc *descriptorpb.ServiceDescriptorProto) {
+				proto.SetExtension(svc.Options, options.E_RegistryTag, &options.RegistryTag{Name: "svc", Targets: []string{"custom"}})
+			},
+			params:  "registry_tags=custom:testdata/invalid.tmpl",
+			wantErr: "error parsing registry tag template custom",
+		},
+		{
+			name:    "missing registry tag template",
+			params:  "registry_tags=custom:testdata/missing.tmpl",
+			wantErr: "error reading registry tag template custom",
+		},
+		{
+			name: "invalid method timeout",
+			set: func(svc *descriptorpb.ServiceDescriptorProto) {
+				opts := &descriptorpb.MethodOptions{}
+				proto.SetExtension(opts, options.E_MethodPolicy, &options.MethodPolicy{Timeout: "soon"})
+				svc.Method[0].Options = opts
+			},
+			wantErr: `invalid timeout "soon"`,
+		},
+		{
+			name: "invalid retryable code",
+			set: func(svc *descriptorpb.ServiceDescriptorProto) {
+				opts := &descriptorpb.MethodOptions{}
+				proto.SetExtension(opts, options.E_MethodPolicy, &options.MethodPolicy{RetryableCodes: []string{"Unavailable"}})
+				svc.Method[0].Options = opts
+			},
+			wantErr: `invalid retryable code "Unavailable"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := loadFiles(t)
+			if tt.set != nil {
+				svc := findFile(files, "example/example.proto").Service[0]
+				if svc.Options == nil {
+					svc.Options = &descriptorpb.ServiceOptions{}
+				}
+
+				tt.set(svc)
+			}
+
+			res := generate(t, files, tt.params)
+			if !strings.Contains(res.GetError(), tt.wantErr) {
+				t.Fatalf("got error %q, want %q", res.GetError(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRegistryTagTemplates(t *testing.T) {
+	tests := []struct {
+		name       string
+		template   string
+		want       []string
+		notWant    []string
+		wantImport bool
+	}{
+		{
+			name:     "literal dollar signs",
+			template: "testdata/literal.tmpl",
+			want: []string{
+				`"traefik.http.middlewares.service-name-unique-rewrite.replacepathregex.replacement=/v1/$1",`,
+				"\"traefik.http.routers.service-name-unique.rule=Host(`\" + os.Getenv(\"BASE_URL\") + \"`) && PathRegexp(`^/books/[0-9]+$$`)\",",
+			},
+			wantImport: true,
+		},
+		{
+			name:     "no env vars",
+			template: "testdata/static.tmpl",
+			want: []string{
+				"\"traefik.http.routers.service-name-unique.rule=PathPrefix(`/your/path/with/or/without/prefix`)\",",
+			},
+			notWant: []string{"os.Getenv"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := loadFiles(t)
+			svc := findFile(files, "example/example.proto").Service[0]
+			tag := proto.GetExtension(svc.Options, options.E_RegistryTag).(*options.RegistryTag)
+			tag = proto.Clone(tag).(*options.RegistryTag)
+			tag.Targets = []string{"custom"}
+			proto.SetExtension(svc.Options, options.E_RegistryTag, tag)
+
+			res := generate(t, files, "registry_tags=custom:"+tt.template)
+			if res.GetError() != "" {
+				t.Fatalf("generating: %s", res.GetError())
+			}
+
+			var content string
+			for _, f := range res.File {
+				if f.GetName() == "example.pb.gs.go" {
+					content = f.GetContent()
+				}
+			}
+
+			for _, want := range tt.want {
+				if !strings.Contains(content, want) {
+					t.Errorf("generated file doesn't contain %s:\n%s", want, content)
+				}
+			}
+
+			for _, notWant := range tt.notWant {
+				if strings.Contains(content, notWant) {
+					t.Errorf("generated file contains %s", notWant)
+				}
+			}
+
+			if got := strings.Contains(content, "\t\"os\"\n"); got != tt.wantImport {
+				t.Errorf("os imported: %v, want %v", got, tt.wantImport)
+			}
+		})
+	}
+}
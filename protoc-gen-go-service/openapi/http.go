@@ -0,0 +1,184 @@
+package openapi
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+// HTTP_EXTENSION is the field number of the google.api.http method option
+const HTTP_EXTENSION = 72295728
+
+// The field numbers of google.api.HttpRule
+const (
+	ruleGet                = 2
+	rulePut                = 3
+	rulePost               = 4
+	ruleDelete             = 5
+	rulePatch              = 6
+	ruleBody               = 7
+	ruleCustom             = 8
+	ruleAdditionalBindings = 11
+	ruleResponseBody       = 12
+	customKind             = 1
+	customPath             = 2
+)
+
+// HTTPRule is an HTTP binding of a method, from the google.api.http option
+type HTTPRule struct {
+	// Method is the HTTP method, e.g. GET
+	Method string
+	// Path is the path template, e.g. /v1/{name=books/*}
+	Path string
+	// Body is the request field mapped to the body, "*" for the whole request
+	Body string
+	// ResponseBody is the response field mapped to the body, empty for the whole response
+	ResponseBody string
+}
+
+// HTTPRules returns the HTTP bindings of the method, including the additional bindings.
+// The option is read from the wire format, without depending on the google.api types.
+func HTTPRules(method *protogen.Method) ([]HTTPRule, error) {
+	opts := method.Desc.Options()
+	if opts == nil {
+		return nil, nil
+	}
+
+	b, err := proto.Marshal(opts)
+	if err != nil {
+		return nil, fmt.Errorf("%s: error reading options: %v", method.Desc.FullName(), err)
+	}
+
+	rules := []HTTPRule{}
+	err = consumeFields(b, func(num protowire.Number, v []byte) error {
+		if num != HTTP_EXTENSION {
+			return nil
+		}
+
+		rule, additional, err := parseRule(v)
+		if err != nil {
+			return err
+		}
+
+		rules = append(append(rules, rule), additional...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid google.api.http option: %v", method.Desc.FullName(), err)
+	}
+
+	return rules, nil
+}
+
+// parseRule returns the rule and its additional bindings
+func parseRule(b []byte) (HTTPRule, []HTTPRule, error) {
+	rule := HTTPRule{}
+	additional := []HTTPRule{}
+	err := consumeFields(b, func(num protowire.Number, v []byte) error {
+		switch num {
+		case ruleGet:
+			rule.Method, rule.Path = "GET", string(v)
+		case rulePut:
+			rule.Method, rule.Path = "PUT", string(v)
+		case rulePost:
+			rule.Method, rule.Path = "POST", string(v)
+		case ruleDelete:
+			rule.Method, rule.Path = "DELETE", string(v)
+		case rulePatch:
+			rule.Method, rule.Path = "PATCH", string(v)
+		case ruleBody:
+			rule.Body = string(v)
+		case ruleResponseBody:
+			rule.ResponseBody = string(v)
+		case ruleCustom:
+			return consumeFields(v, func(num protowire.Number, v []byte) error {
+				switch num {
+				case customKind:
+					rule.Method = strings.ToUpper(string(v))
+				case customPath:
+					rule.Path = string(v)
+				}
+
+				return nil
+			})
+		case ruleAdditionalBindings:
+			// The additional bindings can't be nested
+			binding, _, err := parseRule(v)
+			if err != nil {
+				return err
+			}
+
+			additional = append(additional, binding)
+		}
+
+		return nil
+	})
+
+	return rule, additional, err
+}
+
+// consumeFields calls fn with the length-delimited fields of the message, the other fields are skipped
+func consumeFields(b []byte, fn func(num protowire.Number, v []byte) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		if typ != protowire.BytesType {
+			n = protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			b = b[n:]
+			continue
+		}
+
+		v, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		if err := fn(num, v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pathParams returns the OpenAPI path of the path template and its variables,
+// e.g. /v1/{name} and [name] for /v1/{name=books/*}
+func pathParams(template string) (string, []string) {
+	var path strings.Builder
+	params := []string{}
+	for {
+		start := strings.Index(template, "{")
+		if start < 0 {
+			break
+		}
+
+		end := strings.Index(template[start:], "}")
+		if end < 0 {
+			break
+		}
+		end += start
+
+		name := template[start+1 : end]
+		if i := strings.Index(name, "="); i >= 0 {
+			name = name[:i]
+		}
+
+		params = append(params, name)
+		path.WriteString(template[:start] + "{" + name + "}")
+		template = template[end+1:]
+	}
+
+	path.WriteString(template)
+	return path.String(), params
+}
@@ -0,0 +1,310 @@
+// Package openapi generates the OpenAPI v3 documents of the services exposed by the gateway,
+// using the google.api.http options of their methods.
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	// OPENAPI_VERSION is the version of the OpenAPI specification of the documents
+	OPENAPI_VERSION = "3.0.3"
+	// CONTENT_TYPE is the content type of the requests and responses of the gateway
+	CONTENT_TYPE = "application/json"
+)
+
+// Document is an OpenAPI v3 document
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Tags       []Tag                `json:"tags,omitempty"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+// Info holds the metadata of the API
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// Tag groups the operations of a service
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem holds the operations of a path by HTTP method, e.g. "get"
+type PathItem map[string]*Operation
+
+// Operation is a method bound to an HTTP method and path
+type Operation struct {
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Parameters  []*Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+// Parameter is a path or query parameter of an operation
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Required    bool    `json:"required,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody is the body of the requests of an operation
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response is a response of an operation, or a reference to a component response
+type Response struct {
+	Ref         string               `json:"$ref,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType holds the schema of a content type
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components holds the schemas and responses referenced by the operations
+type Components struct {
+	Schemas   map[string]*Schema   `json:"schemas"`
+	Responses map[string]*Response `json:"responses"`
+}
+
+// New returns the OpenAPI document of the services of the file.
+// All the messages of the file are added to the schemas, the methods without HTTP bindings are skipped.
+func New(f *protogen.File, version string) (*Document, error) {
+	d := &Document{
+		OpenAPI: OPENAPI_VERSION,
+		Info: Info{
+			Title:       f.Desc.Path(),
+			Description: comment(protogen.Comments(f.Desc.SourceLocations().ByPath(protoreflect.SourcePath{2}).LeadingComments)),
+			Version:     version,
+		},
+		Paths: map[string]*PathItem{},
+		Components: Components{
+			Schemas: errorSchemas(),
+			Responses: map[string]*Response{
+				ERROR_RESPONSE: {
+					Description: "The error returned by the gateway",
+					Content:     map[string]MediaType{CONTENT_TYPE: {Schema: ref(ERROR_SCHEMA)}},
+				},
+			},
+		},
+	}
+
+	for _, service := range f.Services {
+		d.Tags = append(d.Tags, Tag{Name: service.GoName, Description: comment(service.Comments.Leading)})
+		for _, method := range service.Methods {
+			if err := d.addMethod(method); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var walk func(messages []*protogen.Message)
+	walk = func(messages []*protogen.Message) {
+		for _, m := range messages {
+			d.addMessage(m)
+			for _, e := range m.Enums {
+				d.addEnum(e)
+			}
+
+			walk(m.Messages)
+		}
+	}
+	walk(f.Messages)
+
+	for _, e := range f.Enums {
+		d.addEnum(e)
+	}
+
+	return d, nil
+}
+
+// GenerateFile generates the .openapi.json file of the services of the file
+func GenerateFile(gen *protogen.Plugin, f *protogen.File, version string) (*protogen.GeneratedFile, error) {
+	d, err := New(f, version)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling the OpenAPI document of %s: %v", f.Desc.Path(), err)
+	}
+
+	gf := gen.NewGeneratedFile(f.GeneratedFilenamePrefix+".openapi.json", f.GoImportPath)
+	if _, err := gf.Write(append(b, '\n')); err != nil {
+		return nil, err
+	}
+
+	return gf, nil
+}
+
+// addMethod adds the operations of the HTTP bindings of the method
+func (d *Document) addMethod(method *protogen.Method) error {
+	rules, err := HTTPRules(method)
+	if err != nil {
+		return err
+	}
+
+	for i, rule := range rules {
+		if rule.Method == "" || rule.Path == "" {
+			return fmt.Errorf("%s: google.api.http option without a pattern", method.Desc.FullName())
+		}
+
+		op, path, err := d.operation(method, rule)
+		if err != nil {
+			return err
+		}
+
+		if i > 0 {
+			op.OperationID = fmt.Sprintf("%s_%d", op.OperationID, i)
+		}
+
+		item, ok := d.Paths[path]
+		if !ok {
+			item = &PathItem{}
+			d.Paths[path] = item
+		}
+
+		(*item)[strings.ToLower(rule.Method)] = op
+	}
+
+	return nil
+}
+
+// operation returns the operation of the HTTP binding of the method and its OpenAPI path
+func (d *Document) operation(method *protogen.Method, rule HTTPRule) (*Operation, string, error) {
+	path, params := pathParams(rule.Path)
+	op := &Operation{
+		OperationID: method.Parent.GoName + "_" + method.GoName,
+		Summary:     comment(method.Comments.Leading),
+		Tags:        []string{method.Parent.GoName},
+		Responses: map[string]*Response{
+			"default": {Ref: "#/components/responses/" + ERROR_RESPONSE},
+		},
+	}
+
+	bound := map[string]bool{}
+	for _, param := range params {
+		field := findField(method.Input, param)
+		if field == nil {
+			return nil, "", fmt.Errorf("%s: path parameter %q isn't a field of %s", method.Desc.FullName(), param, method.Input.Desc.FullName())
+		}
+
+		bound[param] = true
+		op.Parameters = append(op.Parameters, &Parameter{
+			Name:        param,
+			In:          "path",
+			Required:    true,
+			Description: comment(field.Comments.Leading),
+			Schema:      d.valueSchema(field),
+		})
+	}
+
+	switch rule.Body {
+	case "":
+	case "*":
+		op.RequestBody = &RequestBody{Required: true, Content: map[string]MediaType{CONTENT_TYPE: {Schema: d.messageSchema(method.Input)}}}
+	default:
+		field := findField(method.Input, rule.Body)
+		if field == nil {
+			return nil, "", fmt.Errorf("%s: body %q isn't a field of %s", method.Desc.FullName(), rule.Body, method.Input.Desc.FullName())
+		}
+
+		bound[rule.Body] = true
+		op.RequestBody = &RequestBody{Required: true, Content: map[string]MediaType{CONTENT_TYPE: {Schema: d.fieldSchema(field)}}}
+	}
+
+	// The fields neither in the path nor in the body are query parameters
+	if rule.Body != "*" {
+		for _, field := range method.Input.Fields {
+			name := string(field.Desc.Name())
+			if bound[name] || field.Desc.IsMap() || field.Desc.Kind() == protoreflect.MessageKind || field.Desc.Kind() == protoreflect.GroupKind {
+				continue
+			}
+
+			op.Parameters = append(op.Parameters, &Parameter{
+				Name:        field.Desc.JSONName(),
+				In:          "query",
+				Description: comment(field.Comments.Leading),
+				Schema:      d.fieldSchema(field),
+			})
+		}
+	}
+
+	schema := d.messageSchema(method.Output)
+	if rule.ResponseBody != "" {
+		field := findField(method.Output, rule.ResponseBody)
+		if field == nil {
+			return nil, "", fmt.Errorf("%s: response body %q isn't a field of %s", method.Desc.FullName(), rule.ResponseBody, method.Output.Desc.FullName())
+		}
+
+		schema = d.fieldSchema(field)
+	}
+
+	response := &Response{Description: "A successful response"}
+	if method.Desc.IsStreamingServer() {
+		// The gateway streams a JSON object per line, holding either a result or an error
+		response.Description = "A stream of results, one JSON object per line"
+		schema = &Schema{
+			Type: "object",
+			Properties: map[string]*Schema{
+				"result": schema,
+				"error":  ref(STATUS_SCHEMA),
+			},
+		}
+	}
+
+	response.Content = map[string]MediaType{CONTENT_TYPE: {Schema: schema}}
+	op.Responses["200"] = response
+
+	return op, path, nil
+}
+
+// findField returns the field of the message from its path, e.g. "book.name"
+func findField(m *protogen.Message, path string) *protogen.Field {
+	names := strings.Split(path, ".")
+	for i, name := range names {
+		var found *protogen.Field
+		for _, field := range m.Fields {
+			if string(field.Desc.Name()) == name {
+				found = field
+				break
+			}
+		}
+
+		if found == nil {
+			return nil
+		}
+
+		if i == len(names)-1 {
+			return found
+		}
+
+		if found.Message == nil {
+			return nil
+		}
+
+		m = found.Message
+	}
+
+	return nil
+}
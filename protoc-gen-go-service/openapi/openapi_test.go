@@ -0,0 +1,182 @@
+package openapi
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// httpOption returns the method options with the google.api.http option encoded
+func httpOption(pattern protowire.Number, path string, body string, additional ...[]byte) *descriptorpb.MethodOptions {
+	var rule []byte
+	rule = protowire.AppendTag(rule, pattern, protowire.BytesType)
+	rule = protowire.AppendString(rule, path)
+	if body != "" {
+		rule = protowire.AppendTag(rule, ruleBody, protowire.BytesType)
+		rule = protowire.AppendString(rule, body)
+	}
+
+	for _, binding := range additional {
+		rule = protowire.AppendTag(rule, ruleAdditionalBindings, protowire.BytesType)
+		rule = protowire.AppendBytes(rule, binding)
+	}
+
+	var b []byte
+	b = protowire.AppendTag(b, HTTP_EXTENSION, protowire.BytesType)
+	b = protowire.AppendBytes(b, rule)
+
+	opts := &descriptorpb.MethodOptions{}
+	opts.ProtoReflect().SetUnknown(b)
+	return opts
+}
+
+func field(name string, n int32, typ descriptorpb.FieldDescriptorProto_Type, typeName string) *descriptorpb.FieldDescriptorProto {
+	f := &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String(name),
+		Number:   proto.Int32(n),
+		JsonName: proto.String(name),
+		Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		Type:     typ.Enum(),
+	}
+	if typeName != "" {
+		f.TypeName = proto.String(typeName)
+	}
+
+	return f
+}
+
+func newFile(t *testing.T) *protogen.File {
+	var custom []byte
+	custom = protowire.AppendTag(custom, rulePut, protowire.BytesType)
+	custom = protowire.AppendString(custom, "/v1/books/{book.name}")
+
+	fd := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("books.proto"),
+		Package: proto.String("books"),
+		Syntax:  proto.String("proto3"),
+		Options: &descriptorpb.FileOptions{GoPackage: proto.String("example.com/books")},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{Name: proto.String("Book"), Field: []*descriptorpb.FieldDescriptorProto{
+				field("name", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+				field("pages", 2, descriptorpb.FieldDescriptorProto_TYPE_INT64, ""),
+				field("genre", 3, descriptorpb.FieldDescriptorProto_TYPE_ENUM, ".books.Genre"),
+			}},
+			{Name: proto.String("GetBookRequest"), Field: []*descriptorpb.FieldDescriptorProto{
+				field("name", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+				field("view", 2, descriptorpb.FieldDescriptorProto_TYPE_BOOL, ""),
+			}},
+			{Name: proto.String("UpdateBookRequest"), Field: []*descriptorpb.FieldDescriptorProto{
+				field("book", 1, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".books.Book"),
+			}},
+		},
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name: proto.String("Genre"),
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("UNKNOWN"), Number: proto.Int32(0)},
+				{Name: proto.String("NOVEL"), Number: proto.Int32(1)},
+			},
+		}},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("Books"),
+			Method: []*descriptorpb.MethodDescriptorProto{
+				{
+					Name: proto.String("GetBook"), InputType: proto.String(".books.GetBookRequest"), OutputType: proto.String(".books.Book"),
+					Options: httpOption(ruleGet, "/v1/{name=books/*}", ""),
+				},
+				{
+					Name: proto.String("UpdateBook"), InputType: proto.String(".books.UpdateBookRequest"), OutputType: proto.String(".books.Book"),
+					Options: httpOption(rulePatch, "/v1/books/{book.name}", "book", custom),
+				},
+				{
+					Name: proto.String("WatchBook"), InputType: proto.String(".books.GetBookRequest"), OutputType: proto.String(".books.Book"),
+					ServerStreaming: proto.Bool(true),
+					Options:         httpOption(ruleGet, "/v1/{name=books/*}:watch", ""),
+				},
+				{Name: proto.String("Unbound"), InputType: proto.String(".books.Book"), OutputType: proto.String(".books.Book")},
+			},
+		}},
+	}
+
+	gen, err := protogen.Options{}.New(&pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{"books.proto"},
+		ProtoFile:      []*descriptorpb.FileDescriptorProto{fd},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return gen.Files[0]
+}
+
+func TestNew(t *testing.T) {
+	d, err := New(newFile(t), "2.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(d.Paths) != 3 {
+		t.Fatalf("got %d paths, want 3", len(d.Paths))
+	}
+
+	get := (*d.Paths["/v1/{name}"])["get"]
+	if get == nil || get.OperationID != "Books_GetBook" {
+		t.Fatalf("GetBook operation not found: %+v", d.Paths["/v1/{name}"])
+	}
+
+	if len(get.Parameters) != 2 || get.Parameters[0].In != "path" || get.Parameters[1].Name != "view" || get.Parameters[1].In != "query" {
+		t.Errorf("unexpected GetBook parameters %+v", get.Parameters)
+	}
+
+	if get.Responses["200"].Content[CONTENT_TYPE].Schema.Ref != SCHEMA_PREFIX+"books.Book" {
+		t.Errorf("unexpected GetBook response %+v", get.Responses["200"])
+	}
+
+	update := *d.Paths["/v1/books/{book.name}"]
+	if update["patch"] == nil || update["put"] == nil || update["put"].OperationID != "Books_UpdateBook_1" {
+		t.Fatalf("UpdateBook operations not found: %+v", update)
+	}
+
+	if update["patch"].RequestBody.Content[CONTENT_TYPE].Schema.Ref != SCHEMA_PREFIX+"books.Book" {
+		t.Errorf("unexpected UpdateBook body %+v", update["patch"].RequestBody)
+	}
+
+	watch := (*d.Paths["/v1/{name}:watch"])["get"]
+	if watch == nil || watch.Responses["200"].Content[CONTENT_TYPE].Schema.Properties["result"] == nil {
+		t.Fatalf("WatchBook stream response not found: %+v", d.Paths["/v1/{name}:watch"])
+	}
+
+	book := d.Components.Schemas["books.Book"]
+	if book == nil || book.Properties["pages"].Format != "int64" || book.Properties["genre"].Ref != SCHEMA_PREFIX+"books.Genre" {
+		t.Errorf("unexpected Book schema %+v", book)
+	}
+
+	for _, name := range []string{"books.Genre", ERROR_SCHEMA, FIELD_VIOLATION_SCHEMA, STATUS_SCHEMA, ERROR_DETAIL_SCHEMA} {
+		if d.Components.Schemas[name] == nil {
+			t.Errorf("%s schema not found", name)
+		}
+	}
+}
+
+func TestNewInvalidPath(t *testing.T) {
+	f := newFile(t)
+	f.Services[0].Methods[0].Input = f.Services[0].Methods[1].Input
+
+	if _, err := New(f, "2.0.0"); err == nil {
+		t.Fatal("expected an error for the path parameter not in the request")
+	}
+}
+
+func TestPathParams(t *testing.T) {
+	path, params := pathParams("/v1/{parent=shelves/*}/books/{book_id}:publish")
+	if path != "/v1/{parent}/books/{book_id}:publish" {
+		t.Errorf("got path %s", path)
+	}
+
+	if len(params) != 2 || params[0] != "parent" || params[1] != "book_id" {
+		t.Errorf("got params %v", params)
+	}
+}
@@ -0,0 +1,227 @@
+package openapi
+
+import (
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	// SCHEMA_PREFIX is the prefix of the references to the component schemas
+	SCHEMA_PREFIX = "#/components/schemas/"
+	// ERROR_SCHEMA is the schema of the errors returned by the gateway, from errors.Response
+	ERROR_SCHEMA = "errors.Response"
+	// FIELD_VIOLATION_SCHEMA is the schema of the invalid fields of the errors, from errors.FieldViolation
+	FIELD_VIOLATION_SCHEMA = "errors.FieldViolation"
+	// STATUS_SCHEMA is the schema of the errors ending the streams returned by the gateway
+	STATUS_SCHEMA = "google.rpc.Status"
+	// ERROR_DETAIL_SCHEMA is the schema of the error details, from server/grpc/error.ErrorDetail
+	ERROR_DETAIL_SCHEMA = "error.ErrorDetail"
+	// ERROR_RESPONSE is the response of the errors returned by the gateway
+	ERROR_RESPONSE = "Error"
+)
+
+// Schema is an OpenAPI schema
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+}
+
+// ref returns the reference to the component schema
+func ref(name string) *Schema {
+	return &Schema{Ref: SCHEMA_PREFIX + name}
+}
+
+// wellKnown are the schemas of the well-known types, using their JSON mapping
+var wellKnown = map[protoreflect.FullName]func() *Schema{
+	"google.protobuf.Timestamp":   func() *Schema { return &Schema{Type: "string", Format: "date-time"} },
+	"google.protobuf.Duration":    func() *Schema { return &Schema{Type: "string"} },
+	"google.protobuf.FieldMask":   func() *Schema { return &Schema{Type: "string"} },
+	"google.protobuf.Empty":       func() *Schema { return &Schema{Type: "object"} },
+	"google.protobuf.Struct":      func() *Schema { return &Schema{Type: "object"} },
+	"google.protobuf.Value":       func() *Schema { return &Schema{} },
+	"google.protobuf.ListValue":   func() *Schema { return &Schema{Type: "array", Items: &Schema{}} },
+	"google.protobuf.Any":         anySchema,
+	"google.protobuf.DoubleValue": func() *Schema { return &Schema{Type: "number", Format: "double"} },
+	"google.protobuf.FloatValue":  func() *Schema { return &Schema{Type: "number", Format: "float"} },
+	"google.protobuf.Int64Value":  func() *Schema { return &Schema{Type: "string", Format: "int64"} },
+	"google.protobuf.UInt64Value": func() *Schema { return &Schema{Type: "string", Format: "uint64"} },
+	"google.protobuf.Int32Value":  func() *Schema { return &Schema{Type: "integer", Format: "int32"} },
+	"google.protobuf.UInt32Value": func() *Schema { return &Schema{Type: "integer", Format: "int64"} },
+	"google.protobuf.BoolValue":   func() *Schema { return &Schema{Type: "boolean"} },
+	"google.protobuf.StringValue": func() *Schema { return &Schema{Type: "string"} },
+	"google.protobuf.BytesValue":  func() *Schema { return &Schema{Type: "string", Format: "byte"} },
+}
+
+// anySchema returns the schema of google.protobuf.Any
+func anySchema() *Schema {
+	return &Schema{
+		Type:                 "object",
+		Properties:           map[string]*Schema{"@type": {Type: "string"}},
+		AdditionalProperties: &Schema{},
+	}
+}
+
+// errorSchemas returns the schemas of the errors returned by the gateway.
+// The errors of the streams are statuses with the server/grpc/error.ErrorDetail of the errors as details.
+func errorSchemas() map[string]*Schema {
+	return map[string]*Schema{
+		ERROR_SCHEMA: {
+			Type:        "object",
+			Description: "The error returned for the failed calls",
+			Properties: map[string]*Schema{
+				"code":         {Type: "integer", Format: "int32", Description: "The gRPC status code"},
+				"status":       {Type: "string", Description: "The name of the gRPC status code"},
+				"message":      {Type: "string", Description: "The error message"},
+				"violations":   {Type: "array", Items: ref(FIELD_VIOLATION_SCHEMA)},
+				"retryAfterMs": {Type: "integer", Format: "int64", Description: "The delay in milliseconds after which the request can be retried"},
+			},
+		},
+		FIELD_VIOLATION_SCHEMA: {
+			Type:        "object",
+			Description: "FieldViolation describes an invalid field of the request",
+			Properties: map[string]*Schema{
+				"field":       {Type: "string", Description: "field is the path of the field, e.g. address.city"},
+				"description": {Type: "string", Description: "description explains why the field is invalid"},
+			},
+		},
+		STATUS_SCHEMA: {
+			Type:        "object",
+			Description: "The error ending the failed streams",
+			Properties: map[string]*Schema{
+				"code":    {Type: "integer", Format: "int32", Description: "The gRPC status code"},
+				"message": {Type: "string", Description: "The error message"},
+				"details": {Type: "array", Items: ref(ERROR_DETAIL_SCHEMA)},
+			},
+		},
+		ERROR_DETAIL_SCHEMA: {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"@type":        {Type: "string", Description: "type.googleapis.com/error.ErrorDetail"},
+				"code":         {Type: "integer", Format: "int32", Description: "code is the unique error instance identifier."},
+				"status":       {Type: "string"},
+				"message":      {Type: "string", Description: "message describes the specific error occurrence."},
+				"public":       {Type: "boolean", Description: "public indicates the error can be displayed publically"},
+				"stackEntries": {Type: "array", Items: &Schema{Type: "string"}},
+				"violations":   {Type: "array", Items: ref(FIELD_VIOLATION_SCHEMA)},
+				"retryAfterMs": {Type: "string", Format: "int64", Description: "retry_after_ms is the delay in milliseconds after which the request can be retried"},
+			},
+		},
+	}
+}
+
+// comment returns the comment without the surrounding spaces
+func comment(c protogen.Comments) string {
+	return strings.TrimSpace(string(c))
+}
+
+// addMessage adds the schema of the message, and of the messages and enums of its fields, to the components
+func (d *Document) addMessage(m *protogen.Message) {
+	name := string(m.Desc.FullName())
+	if _, ok := wellKnown[m.Desc.FullName()]; ok || m.Desc.IsMapEntry() {
+		return
+	}
+
+	if _, ok := d.Components.Schemas[name]; ok {
+		return
+	}
+
+	s := &Schema{
+		Type:        "object",
+		Description: comment(m.Comments.Leading),
+		Properties:  map[string]*Schema{},
+	}
+	d.Components.Schemas[name] = s
+
+	for _, field := range m.Fields {
+		s.Properties[field.Desc.JSONName()] = d.fieldSchema(field)
+	}
+}
+
+// addEnum adds the schema of the enum to the components
+func (d *Document) addEnum(e *protogen.Enum) {
+	name := string(e.Desc.FullName())
+	if _, ok := d.Components.Schemas[name]; ok {
+		return
+	}
+
+	s := &Schema{Type: "string", Description: comment(e.Comments.Leading)}
+	for _, value := range e.Values {
+		s.Enum = append(s.Enum, string(value.Desc.Name()))
+	}
+
+	d.Components.Schemas[name] = s
+}
+
+// fieldSchema returns the schema of the field
+func (d *Document) fieldSchema(field *protogen.Field) *Schema {
+	if field.Desc.IsMap() {
+		return &Schema{
+			Type:                 "object",
+			Description:          comment(field.Comments.Leading),
+			AdditionalProperties: d.valueSchema(field.Message.Fields[1]),
+		}
+	}
+
+	s := d.valueSchema(field)
+	if field.Desc.IsList() {
+		s = &Schema{Type: "array", Items: s}
+	}
+
+	if s.Ref == "" {
+		s.Description = comment(field.Comments.Leading)
+	}
+
+	return s
+}
+
+// valueSchema returns the schema of a single value of the field, using the protobuf JSON mapping
+func (d *Document) valueSchema(field *protogen.Field) *Schema {
+	switch field.Desc.Kind() {
+	case protoreflect.BoolKind:
+		return &Schema{Type: "boolean"}
+	case protoreflect.StringKind:
+		return &Schema{Type: "string"}
+	case protoreflect.BytesKind:
+		return &Schema{Type: "string", Format: "byte"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return &Schema{Type: "integer", Format: "int32"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return &Schema{Type: "integer", Format: "int64"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return &Schema{Type: "string", Format: "int64"}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return &Schema{Type: "string", Format: "uint64"}
+	case protoreflect.FloatKind:
+		return &Schema{Type: "number", Format: "float"}
+	case protoreflect.DoubleKind:
+		return &Schema{Type: "number", Format: "double"}
+	case protoreflect.EnumKind:
+		d.addEnum(field.Enum)
+		return ref(string(field.Enum.Desc.FullName()))
+	default:
+		if schema, ok := wellKnown[field.Message.Desc.FullName()]; ok {
+			return schema()
+		}
+
+		d.addMessage(field.Message)
+		return ref(string(field.Message.Desc.FullName()))
+	}
+}
+
+// messageSchema returns the schema of the message, added to the components
+func (d *Document) messageSchema(m *protogen.Message) *Schema {
+	if schema, ok := wellKnown[m.Desc.FullName()]; ok {
+		return schema()
+	}
+
+	d.addMessage(m)
+	return ref(string(m.Desc.FullName()))
+}
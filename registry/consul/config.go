@@ -1,6 +1,10 @@
 package consul
 
-import "github.com/easeq/go-service/component"
+import (
+	"time"
+
+	"github.com/easeq/go-service/component"
+)
 
 // Config - consul configuration
 type Config struct {
@@ -8,6 +12,8 @@ type Config struct {
 	Host        string `env:"CONSUL_HOST,default=localhost"`
 	Port        int    `env:"CONSUL_PORT,default=8500"`
 	TTL         int    `env:"CONSUL_TTL,default=15"`
+	// DeregisterCriticalAfter is the time after which consul removes an instance failing its TTL check
+	DeregisterCriticalAfter time.Duration `env:"CONSUL_DEREGISTER_CRITICAL_AFTER,default=1m"`
 }
 
 // NewConfig returns the parsed config for jetstream from env
@@ -17,3 +23,18 @@ func NewConfig() *Config {
 
 	return c
 }
+
+// GetTTL returns the TTL of the service check
+func (c *Config) GetTTL() time.Duration {
+	return time.Duration(c.TTL) * time.Second
+}
+
+// HeartbeatInterval returns the interval at which the TTL check is passed
+func (c *Config) HeartbeatInterval() time.Duration {
+	interval := c.GetTTL() / 3
+	if interval < 100*time.Millisecond {
+		return 100 * time.Millisecond
+	}
+
+	return interval
+}
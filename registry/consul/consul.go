@@ -4,13 +4,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/easeq/go-service/component"
 	"github.com/easeq/go-service/logger"
+	"github.com/easeq/go-service/registry"
 	"github.com/easeq/go-service/server"
+	"github.com/easeq/go-service/utils"
 
 	"github.com/Netflix/go-env"
-	"github.com/easeq/go-consul-registry/v2/consul"
+	"github.com/hashicorp/consul/api"
 )
 
 var (
@@ -20,9 +24,15 @@ var (
 
 // Consul registry
 type Consul struct {
-	i      component.Initializer
-	logger logger.Logger
-	server server.Server
+	i            component.Initializer
+	logger       logger.Logger
+	server       server.Server
+	client       *api.Client
+	id           string
+	registration *api.AgentServiceRegistration
+	cancel       context.CancelFunc
+	done         chan struct{}
+	mu           sync.Mutex
 	*Config
 }
 
@@ -34,24 +44,40 @@ func (c *Config) UnmarshalEnv(es env.EnvSet) error {
 // NewConsul returns a new consul registry
 func NewConsul() *Consul {
 	c := &Consul{Config: NewConfig()}
+	c.id = fmt.Sprintf("%s-%s", c.ServiceName, utils.NewID())
+
+	client, err := api.NewClient(&api.Config{Address: c.Address()})
+	if err != nil {
+		panic("error creating consul client")
+	}
+
+	c.client = client
 	c.i = NewInitializer(c)
 	return c
 }
 
-// Register registers service with the registry.
+// Register registers the service instance with the registry,
+// and starts the TTL heartbeat that keeps the instance healthy.
 func (c *Consul) Register(
 	ctx context.Context,
 	server server.Server,
 ) error {
-	if err := consul.Register(
-		ctx,
-		c.ServiceName,
-		server.Host(),
-		server.Port(),
-		c.Address(),
-		c.TTL,
-		server.RegistryTags()...,
-	); err != nil {
+	registration := &api.AgentServiceRegistration{
+		ID:      c.id,
+		Name:    c.ServiceName,
+		Address: server.Host(),
+		Port:    server.Port(),
+		Tags:    server.RegistryTags(),
+		Check: &api.AgentServiceCheck{
+			CheckID:                        c.CheckID(),
+			TTL:                            c.GetTTL().String(),
+			DeregisterCriticalServiceAfter: c.DeregisterCriticalAfter.String(),
+			// The instance is resolved before its first heartbeat, and after a re-registration
+			Status: api.HealthPassing,
+		},
+	}
+
+	if err := c.client.Agent().ServiceRegister(registration); err != nil {
 		c.logger.Errorw(
 			"Service registration failed",
 			"error", err.Error(),
@@ -59,10 +85,101 @@ func (c *Consul) Register(
 		return err
 	}
 
-	c.logger.Infof("Successfully registered service: %s", c.ServiceName)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.registration = registration
+	if c.cancel == nil {
+		hctx, cancel := context.WithCancel(context.Background())
+		c.cancel = cancel
+		c.done = make(chan struct{})
+		go c.heartbeat(hctx, c.done)
+	}
+
+	c.logger.Infof("Successfully registered service: %s (%s)", c.ServiceName, c.id)
+	return nil
+}
+
+// Deregister stops the heartbeat and deregisters the service instance from the registry.
+// It does nothing if the instance isn't registered.
+func (c *Consul) Deregister(ctx context.Context) error {
+	c.mu.Lock()
+	cancel, done, registration := c.cancel, c.done, c.registration
+	c.cancel = nil
+	c.registration = nil
+	c.mu.Unlock()
+
+	if registration == nil {
+		return nil
+	}
+
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+
+	if err := c.client.Agent().ServiceDeregister(c.id); err != nil {
+		c.logger.Errorw(
+			"Service deregistration failed",
+			"error", err.Error(),
+		)
+		return err
+	}
+
+	c.logger.Infof("Successfully deregistered service: %s (%s)", c.ServiceName, c.id)
 	return nil
 }
 
+// heartbeat passes the TTL check of the instance until the context is done.
+// The instance is registered again if consul lost it, e.g. after a restart.
+// done is closed on return, it's passed in as c.done is replaced by the next Register.
+func (c *Consul) heartbeat(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(c.HeartbeatInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := c.client.Agent().UpdateTTL(c.CheckID(), "", api.HealthPassing)
+		if err == nil {
+			continue
+		}
+
+		c.logger.Errorw("Service heartbeat failed", "id", c.id, "error", err.Error())
+
+		c.mu.Lock()
+		registration := c.registration
+		c.mu.Unlock()
+
+		if registration == nil {
+			continue
+		}
+
+		if err := c.client.Agent().ServiceRegister(registration); err != nil {
+			c.logger.Errorw("Service re-registration failed", "id", c.id, "error", err.Error())
+			continue
+		}
+
+		c.logger.Infof("Successfully re-registered service: %s (%s)", c.ServiceName, c.id)
+	}
+}
+
+// ID returns the unique ID of the service instance
+func (c *Consul) ID() string {
+	return c.id
+}
+
+// CheckID returns the ID of the TTL check of the service instance
+func (c *Consul) CheckID() string {
+	return fmt.Sprintf("service:%s", c.id)
+}
+
 // ConnectionString returns the formatted connection string using the config loaded
 func (c *Consul) ConnectionString(args ...interface{}) string {
 	return fmt.Sprintf(
@@ -73,6 +190,22 @@ func (c *Consul) ConnectionString(args ...interface{}) string {
 	)
 }
 
+// Resolve returns the instances of the service registered with consul
+func (c *Consul) Resolve(ctx context.Context, name string) ([]*registry.Instance, error) {
+	q := &api.QueryOptions{}
+	entries, _, err := c.client.Health().Service(name, "", false, q.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("consul resolve error: %v", err)
+	}
+
+	return toInstances(entries), nil
+}
+
+// Watch watches the instances of the service registered with consul
+func (c *Consul) Watch(ctx context.Context, name string) (registry.Watcher, error) {
+	return newWatcher(ctx, c.client, name), nil
+}
+
 // Address returns the prepared consul address
 func (c *Consul) Address() string {
 	return fmt.Sprintf("%s:%d", c.Host, c.Port)
@@ -0,0 +1,173 @@
+package consul
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/easeq/go-service/logger"
+	"github.com/easeq/go-service/server"
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/require"
+)
+
+type nopLogger struct {
+	logger.Logger
+}
+
+func (nopLogger) Infof(template string, args ...interface{}) {}
+
+func (nopLogger) Errorw(message string, args ...interface{}) {}
+
+type testServer struct {
+	server.Server
+}
+
+func (testServer) Host() string { return "127.0.0.1" }
+
+func (testServer) Port() int { return 8080 }
+
+func (testServer) RegistryTags() []string { return []string{"test"} }
+
+// agent is a fake consul agent that loses its registrations when restarted
+type agent struct {
+	services        map[string]*api.AgentServiceRegistration
+	updates         int
+	deregistrations int
+	sync.Mutex
+}
+
+func (a *agent) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.Lock()
+	defer a.Unlock()
+
+	switch {
+	case r.URL.Path == "/v1/agent/service/register":
+		registration := new(api.AgentServiceRegistration)
+		if err := json.NewDecoder(r.Body).Decode(registration); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		a.services[registration.ID] = registration
+	case strings.HasPrefix(r.URL.Path, "/v1/agent/check/update/"):
+		id := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/v1/agent/check/update/"), "service:")
+		if _, ok := a.services[id]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		a.updates++
+	case strings.HasPrefix(r.URL.Path, "/v1/agent/service/deregister/"):
+		delete(a.services, strings.TrimPrefix(r.URL.Path, "/v1/agent/service/deregister/"))
+		a.deregistrations++
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (a *agent) restart() {
+	a.Lock()
+	defer a.Unlock()
+
+	a.services = make(map[string]*api.AgentServiceRegistration)
+}
+
+func (a *agent) registered(id string) bool {
+	a.Lock()
+	defer a.Unlock()
+
+	_, ok := a.services[id]
+	return ok
+}
+
+func newTestConsul(t *testing.T, a *agent) *Consul {
+	ts := httptest.NewServer(a)
+	t.Cleanup(ts.Close)
+
+	client, err := api.NewClient(&api.Config{Address: strings.TrimPrefix(ts.URL, "http://")})
+	require.NoError(t, err)
+
+	c := NewConsul()
+	c.client = client
+	c.logger = nopLogger{}
+	c.TTL = 0
+	return c
+}
+
+func TestRegisterLifecycle(t *testing.T) {
+	a := &agent{services: make(map[string]*api.AgentServiceRegistration)}
+	c := newTestConsul(t, a)
+	replica := newTestConsul(t, a)
+	require.NotEqual(t, c.ID(), replica.ID())
+
+	require.NoError(t, c.Register(context.Background(), testServer{}))
+	require.True(t, a.registered(c.ID()))
+
+	// The instance is passing until its first heartbeat
+	a.Lock()
+	require.Equal(t, api.HealthPassing, a.services[c.ID()].Check.Status)
+	a.Unlock()
+
+	// The heartbeat passes the TTL check
+	require.Eventually(t, func() bool {
+		a.Lock()
+		defer a.Unlock()
+		return a.updates > 0
+	}, time.Second, 10*time.Millisecond)
+
+	// The instance is registered again after a consul restart
+	a.restart()
+	require.Eventually(t, func() bool {
+		return a.registered(c.ID())
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, c.Deregister(context.Background()))
+	require.False(t, a.registered(c.ID()))
+
+	// The heartbeat stopped, the instance is not registered again
+	time.Sleep(3 * c.HeartbeatInterval())
+	require.False(t, a.registered(c.ID()))
+}
+
+func TestHeartbeatDone(t *testing.T) {
+	a := &agent{services: make(map[string]*api.AgentServiceRegistration)}
+	c := newTestConsul(t, a)
+
+	// A Register running while Deregister waits for the heartbeat
+	// replaces c.done, the stopped heartbeat closes its own channel only
+	done := make(chan struct{})
+	c.done = make(chan struct{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c.heartbeat(ctx, done)
+
+	select {
+	case <-done:
+	default:
+		t.Fatal("heartbeat done channel not closed")
+	}
+
+	select {
+	case <-c.done:
+		t.Fatal("done channel of the next heartbeat closed")
+	default:
+	}
+}
+
+func TestDeregisterWithoutRegister(t *testing.T) {
+	a := &agent{services: make(map[string]*api.AgentServiceRegistration)}
+	c := newTestConsul(t, a)
+
+	require.NoError(t, c.Deregister(context.Background()))
+
+	a.Lock()
+	defer a.Unlock()
+	require.Zero(t, a.deregistrations)
+}
@@ -45,13 +45,12 @@ func (i *Initializer) Run(ctx context.Context) error {
 	return i.c.Register(ctx, i.c.server)
 }
 
-// CanRun returns true if the component has anything to Run
+// CanStop returns true if the component has anything to Stop
 func (i *Initializer) CanStop() bool {
-	return false
+	return true
 }
 
-// Stop - stops the running
+// Stop - stops the heartbeat and deregisters the service
 func (i *Initializer) Stop(ctx context.Context) error {
-	i.c.logger.Infow("Unimplemented")
-	return nil
+	return i.c.Deregister(ctx)
 }
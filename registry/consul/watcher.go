@@ -0,0 +1,99 @@
+package consul
+
+import (
+	"context"
+	"time"
+
+	"github.com/easeq/go-service/registry"
+	"github.com/hashicorp/consul/api"
+)
+
+const (
+	// WATCH_WAIT_TIME is the maximum duration of a blocking query for service changes
+	WATCH_WAIT_TIME = 5 * time.Minute
+)
+
+// watcher watches the instances of a service using consul blocking queries
+type watcher struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	client *api.Client
+	name   string
+	index  uint64
+}
+
+func newWatcher(ctx context.Context, client *api.Client, name string) *watcher {
+	ctx, cancel := context.WithCancel(ctx)
+	return &watcher{
+		ctx:    ctx,
+		cancel: cancel,
+		client: client,
+		name:   name,
+	}
+}
+
+// Next blocks until the instances of the service or their health change and returns them
+func (w *watcher) Next() ([]*registry.Instance, error) {
+	for {
+		q := &api.QueryOptions{WaitIndex: w.index, WaitTime: WATCH_WAIT_TIME}
+		entries, meta, err := w.client.Health().Service(w.name, "", false, q.WithContext(w.ctx))
+		if w.ctx.Err() != nil {
+			return nil, registry.ErrWatcherStopped
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		// The blocking query timed out without any change
+		if w.index != 0 && meta.LastIndex == w.index {
+			continue
+		}
+
+		// Reset the index if it goes backwards, as recommended by consul
+		w.index = meta.LastIndex
+		if w.index < q.WaitIndex {
+			w.index = 0
+		}
+
+		return toInstances(entries), nil
+	}
+}
+
+// Stop stops watching the service
+func (w *watcher) Stop() {
+	w.cancel()
+}
+
+// toInstances converts the consul service entries to registry instances
+func toInstances(entries []*api.ServiceEntry) []*registry.Instance {
+	instances := make([]*registry.Instance, 0, len(entries))
+	for _, entry := range entries {
+		address := entry.Service.Address
+		if address == "" {
+			address = entry.Node.Address
+		}
+
+		instances = append(instances, &registry.Instance{
+			ID:      entry.Service.ID,
+			Address: address,
+			Port:    entry.Service.Port,
+			Tags:    entry.Service.Tags,
+			Health:  toHealth(entry.Checks.AggregatedStatus()),
+		})
+	}
+
+	return instances
+}
+
+// toHealth converts the consul health status to the registry instance health
+func toHealth(status string) string {
+	switch status {
+	case api.HealthPassing:
+		return registry.HEALTH_PASSING
+	case api.HealthWarning:
+		return registry.HEALTH_WARNING
+	default:
+		return registry.HEALTH_CRITICAL
+	}
+}
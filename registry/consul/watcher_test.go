@@ -0,0 +1,37 @@
+package consul
+
+import (
+	"testing"
+
+	"github.com/easeq/go-service/registry"
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToInstances(t *testing.T) {
+	entries := []*api.ServiceEntry{
+		{
+			Node:    &api.Node{Address: "10.0.0.1"},
+			Service: &api.AgentService{ID: "svc-1", Address: "10.0.1.1", Port: 8080, Tags: []string{"a"}},
+			Checks:  api.HealthChecks{{Status: api.HealthPassing}},
+		},
+		{
+			Node:    &api.Node{Address: "10.0.0.2"},
+			Service: &api.AgentService{ID: "svc-2", Port: 8080},
+			Checks:  api.HealthChecks{{Status: api.HealthPassing}, {Status: api.HealthWarning}},
+		},
+		{
+			Node:    &api.Node{Address: "10.0.0.3"},
+			Service: &api.AgentService{ID: "svc-3", Port: 8080},
+			Checks:  api.HealthChecks{{Status: api.HealthMaint}},
+		},
+	}
+
+	instances := toInstances(entries)
+	require.Equal(t, []*registry.Instance{
+		{ID: "svc-1", Address: "10.0.1.1", Port: 8080, Tags: []string{"a"}, Health: registry.HEALTH_PASSING},
+		{ID: "svc-2", Address: "10.0.0.2", Port: 8080, Health: registry.HEALTH_WARNING},
+		{ID: "svc-3", Address: "10.0.0.3", Port: 8080, Health: registry.HEALTH_CRITICAL},
+	}, instances)
+	require.Equal(t, instances[:1], registry.Healthy(instances))
+}
@@ -0,0 +1,53 @@
+package dns
+
+import (
+	"time"
+
+	"github.com/Netflix/go-env"
+	"github.com/easeq/go-service/component"
+)
+
+const (
+	// MODE_A resolves the A/AAAA records of the service, e.g. a Kubernetes headless service
+	MODE_A = "a"
+	// MODE_SRV resolves the SRV records of the service
+	MODE_SRV = "srv"
+)
+
+// Config holds the DNS registry configuration
+type Config struct {
+	// Mode is the type of records resolved, either "a" or "srv"
+	Mode string `env:"REGISTRY_DNS_MODE,default=a"`
+	// Domain is appended to the service name, e.g. "default.svc.cluster.local"
+	Domain string `env:"REGISTRY_DNS_DOMAIN"`
+	// Port is the port of the instances resolved from A/AAAA records
+	Port int `env:"REGISTRY_DNS_PORT,default=8080"`
+	// SRVService is the service name of the SRV records, e.g. the Kubernetes port name
+	SRVService string `env:"REGISTRY_DNS_SRV_SERVICE,default=grpc"`
+	// SRVProto is the protocol of the SRV records
+	SRVProto string `env:"REGISTRY_DNS_SRV_PROTO,default=tcp"`
+	// RefreshInterval is the interval at which the records are resolved while watching
+	RefreshInterval time.Duration `env:"REGISTRY_DNS_REFRESH_INTERVAL,default=30s"`
+}
+
+// NewConfig returns the parsed config for the DNS registry from env
+func NewConfig() *Config {
+	c := new(Config)
+	component.NewConfig(c)
+
+	return c
+}
+
+// UnmarshalEnv env.EnvSet to Config
+func (c *Config) UnmarshalEnv(es env.EnvSet) error {
+	return env.Unmarshal(es, c)
+}
+
+// Host returns the DNS name of the service
+func (c *Config) Host(name string) string {
+	if c.Domain == "" {
+		return name
+	}
+
+	return name + "." + c.Domain
+}
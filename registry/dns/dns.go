@@ -0,0 +1,197 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/easeq/go-service/component"
+	"github.com/easeq/go-service/logger"
+	"github.com/easeq/go-service/registry"
+	"github.com/easeq/go-service/server"
+)
+
+var (
+	// ErrInvalidMode returned when the DNS mode is neither MODE_A nor MODE_SRV
+	ErrInvalidMode = errors.New("invalid DNS registry mode")
+)
+
+// Lookuper looks up the DNS records, *net.Resolver implements it
+type Lookuper interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+}
+
+// Option to pass as arg while creating new DNS registry
+type Option func(*DNS)
+
+// DNS registry resolves the services using DNS records,
+// e.g. Kubernetes headless services or SRV records.
+// The instances are registered by the platform, so Register is a no-op.
+type DNS struct {
+	i        component.Initializer
+	logger   logger.Logger
+	resolver Lookuper
+	*Config
+}
+
+// NewDNS returns a new DNS registry
+func NewDNS(opts ...Option) *DNS {
+	d := &DNS{
+		resolver: net.DefaultResolver,
+		Config:   NewConfig(),
+	}
+
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	d.i = NewInitializer(d)
+	return d
+}
+
+// WithResolver sets the resolver used to look up the records
+func WithResolver(resolver Lookuper) Option {
+	return func(d *DNS) {
+		d.resolver = resolver
+	}
+}
+
+// Register is a no-op, the instances are registered in DNS by the platform
+func (d *DNS) Register(ctx context.Context, server server.Server) error {
+	return nil
+}
+
+// Resolve looks up the DNS records of the service and returns its instances sorted by ID
+func (d *DNS) Resolve(ctx context.Context, name string) ([]*registry.Instance, error) {
+	var instances []*registry.Instance
+
+	switch d.Mode {
+	case MODE_A:
+		hosts, err := d.resolver.LookupHost(ctx, d.Host(name))
+		if err != nil {
+			return nil, fmt.Errorf("dns lookup error: %v", err)
+		}
+
+		for _, host := range hosts {
+			instances = append(instances, newInstance(host, d.Port))
+		}
+	case MODE_SRV:
+		_, records, err := d.resolver.LookupSRV(ctx, d.SRVService, d.SRVProto, d.Host(name))
+		if err != nil {
+			return nil, fmt.Errorf("dns srv lookup error: %v", err)
+		}
+
+		for _, record := range records {
+			instances = append(instances, newInstance(strings.TrimSuffix(record.Target, "."), int(record.Port)))
+		}
+	default:
+		return nil, ErrInvalidMode
+	}
+
+	sort.Slice(instances, func(i, j int) bool {
+		return instances[i].ID < instances[j].ID
+	})
+
+	return instances, nil
+}
+
+// newInstance returns a new instance, the DNS records only contain the live instances
+func newInstance(host string, port int) *registry.Instance {
+	return &registry.Instance{
+		ID:      net.JoinHostPort(host, strconv.Itoa(port)),
+		Address: host,
+		Port:    port,
+		Health:  registry.HEALTH_PASSING,
+	}
+}
+
+// Watch resolves the service at every refresh interval and returns the instances when they change
+func (d *DNS) Watch(ctx context.Context, name string) (registry.Watcher, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &watcher{ctx: ctx, cancel: cancel, d: d, name: name}, nil
+}
+
+// ConnectionString returns the DNS target of the service
+func (d *DNS) ConnectionString(args ...interface{}) string {
+	return fmt.Sprintf("dns:///%s:%d", d.Host(fmt.Sprint(args[0])), d.Port)
+}
+
+// Address returns an empty string, the system resolver is used
+func (d *DNS) Address() string {
+	return ""
+}
+
+// ToString returns the string name of the service registry
+func (d *DNS) ToString() string {
+	return "dns"
+}
+
+func (d *DNS) HasInitializer() bool {
+	return true
+}
+
+func (d *DNS) Initializer() component.Initializer {
+	return d.i
+}
+
+// watcher polls the DNS records of a service
+type watcher struct {
+	ctx       context.Context
+	cancel    context.CancelFunc
+	d         *DNS
+	name      string
+	instances []*registry.Instance
+	resolved  bool
+}
+
+// Next blocks until the instances of the service change and returns them
+func (w *watcher) Next() ([]*registry.Instance, error) {
+	if !w.resolved {
+		return w.resolve()
+	}
+
+	ticker := time.NewTicker(w.d.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			return nil, registry.ErrWatcherStopped
+		case <-ticker.C:
+		}
+
+		prev := w.instances
+		instances, err := w.resolve()
+		if err != nil || !reflect.DeepEqual(prev, instances) {
+			return instances, err
+		}
+	}
+}
+
+// resolve resolves the instances of the service
+func (w *watcher) resolve() ([]*registry.Instance, error) {
+	instances, err := w.d.Resolve(w.ctx, w.name)
+	if w.ctx.Err() != nil {
+		return nil, registry.ErrWatcherStopped
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	w.instances = instances
+	w.resolved = true
+	return instances, nil
+}
+
+// Stop stops watching the service
+func (w *watcher) Stop() {
+	w.cancel()
+}
@@ -0,0 +1,95 @@
+package dns
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/easeq/go-service/registry"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeResolver struct {
+	hosts []string
+	srv   []*net.SRV
+	sync.Mutex
+}
+
+func (r *fakeResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	return r.hosts, nil
+}
+
+func (r *fakeResolver) LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+	return "", r.srv, nil
+}
+
+func (r *fakeResolver) setHosts(hosts ...string) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.hosts = hosts
+}
+
+func TestResolve(t *testing.T) {
+	r := &fakeResolver{
+		hosts: []string{"10.0.0.2", "10.0.0.1"},
+		srv:   []*net.SRV{{Target: "orders-0.orders.default.svc.cluster.local.", Port: 9090}},
+	}
+
+	d := NewDNS(WithResolver(r))
+	d.Mode = MODE_A
+	d.Port = 8080
+
+	instances, err := d.Resolve(context.Background(), "orders")
+	require.NoError(t, err)
+	require.Equal(t, []*registry.Instance{
+		{ID: "10.0.0.1:8080", Address: "10.0.0.1", Port: 8080, Health: registry.HEALTH_PASSING},
+		{ID: "10.0.0.2:8080", Address: "10.0.0.2", Port: 8080, Health: registry.HEALTH_PASSING},
+	}, instances)
+
+	d.Mode = MODE_SRV
+	instances, err = d.Resolve(context.Background(), "orders")
+	require.NoError(t, err)
+	require.Equal(t, []*registry.Instance{
+		{
+			ID:      "orders-0.orders.default.svc.cluster.local:9090",
+			Address: "orders-0.orders.default.svc.cluster.local",
+			Port:    9090,
+			Health:  registry.HEALTH_PASSING,
+		},
+	}, instances)
+
+	d.Mode = "invalid"
+	_, err = d.Resolve(context.Background(), "orders")
+	require.ErrorIs(t, err, ErrInvalidMode)
+}
+
+func TestWatch(t *testing.T) {
+	r := &fakeResolver{hosts: []string{"10.0.0.1"}}
+	d := NewDNS(WithResolver(r))
+	d.Mode = MODE_A
+	d.RefreshInterval = time.Millisecond
+
+	w, err := d.Watch(context.Background(), "orders")
+	require.NoError(t, err)
+	defer w.Stop()
+
+	instances, err := w.Next()
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+
+	// Next returns only once the records change
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		r.setHosts("10.0.0.1", "10.0.0.2")
+	}()
+
+	instances, err = w.Next()
+	require.NoError(t, err)
+	require.Len(t, instances, 2)
+}
@@ -0,0 +1,54 @@
+package dns
+
+import (
+	"context"
+
+	"github.com/easeq/go-service/logger"
+)
+
+type Initializer struct {
+	d *DNS
+}
+
+// NewInitializer returns a new DNS registry Initialiazer
+func NewInitializer(d *DNS) *Initializer {
+	return &Initializer{d}
+}
+
+// AddDependency adds necessary service components as dependencies
+func (i *Initializer) AddDependency(dep interface{}) error {
+	switch v := dep.(type) {
+	case logger.Logger:
+		i.d.logger = v
+	}
+
+	return nil
+}
+
+// Dependencies returns the string names of service components
+// that are required as dependencies for this component
+func (i *Initializer) Dependencies() []string {
+	return []string{logger.LOGGER}
+}
+
+// CanRun returns true if the component has anything to Run
+func (i *Initializer) CanRun() bool {
+	return false
+}
+
+// Run start the service component
+func (i *Initializer) Run(ctx context.Context) error {
+	i.d.logger.Infow("Unimplemented")
+	return nil
+}
+
+// CanStop returns true if the component has anything to Stop
+func (i *Initializer) CanStop() bool {
+	return false
+}
+
+// Stop - stops the running
+func (i *Initializer) Stop(ctx context.Context) error {
+	i.d.logger.Infow("Unimplemented")
+	return nil
+}
@@ -0,0 +1,55 @@
+package etcd
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Netflix/go-env"
+	"github.com/easeq/go-service/component"
+)
+
+// Config holds the etcd registry configuration
+type Config struct {
+	ServiceName string `env:"SERVICE_NAME"`
+	// Endpoints is a comma separated list of etcd URLs
+	Endpoints string `env:"REGISTRY_ETCD_ENDPOINTS,default=127.0.0.1:2379"`
+	// DialTimeout is the timeout for failing to establish a connection
+	DialTimeout time.Duration `env:"REGISTRY_ETCD_DIALTIMEOUT,default=5s"`
+	// Username is a username for authentication
+	Username string `env:"REGISTRY_ETCD_USERNAME,omitempty"`
+	// Password is the password for authentication
+	Password string `env:"REGISTRY_ETCD_PASSWORD,omitempty"`
+	// Prefix is the key prefix under which the services are registered
+	Prefix string `env:"REGISTRY_ETCD_PREFIX,default=/go-service/registry"`
+	// TTL is the lease TTL in seconds, an instance is removed when its lease expires
+	TTL int64 `env:"REGISTRY_ETCD_TTL,default=15"`
+	// RetryBackoff is the time to wait before registering again after losing the lease
+	RetryBackoff time.Duration `env:"REGISTRY_ETCD_RETRY_BACKOFF,default=1s"`
+}
+
+// NewConfig returns the parsed config for the etcd registry from env
+func NewConfig() *Config {
+	c := new(Config)
+	component.NewConfig(c)
+
+	return c
+}
+
+// UnmarshalEnv env.EnvSet to Config
+func (c *Config) UnmarshalEnv(es env.EnvSet) error {
+	return env.Unmarshal(es, c)
+}
+
+// GetEndpoints return the etcd server endpoints
+func (c *Config) GetEndpoints() []string {
+	if c.Endpoints == "" {
+		return []string{}
+	}
+
+	return strings.Split(c.Endpoints, ",")
+}
+
+// ServicePrefix returns the key prefix of the instances of the service
+func (c *Config) ServicePrefix(name string) string {
+	return strings.TrimSuffix(c.Prefix, "/") + "/" + name + "/"
+}
@@ -0,0 +1,253 @@
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/easeq/go-service/component"
+	"github.com/easeq/go-service/logger"
+	"github.com/easeq/go-service/registry"
+	"github.com/easeq/go-service/server"
+	"github.com/easeq/go-service/utils"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+var (
+	// ErrCreatingEtcdClient returned when creating etcd clientv3 fails
+	ErrCreatingEtcdClient = errors.New("error creating registry etcd client")
+)
+
+// Etcd registry, the instances are saved under the service key prefix
+// with a lease that is kept alive while the service is running
+type Etcd struct {
+	i        component.Initializer
+	logger   logger.Logger
+	server   server.Server
+	id       string
+	instance *registry.Instance
+	lease    clientv3.LeaseID
+	cancel   context.CancelFunc
+	done     chan struct{}
+	mu       sync.Mutex
+	Client   *clientv3.Client
+	*Config
+}
+
+// NewEtcd returns a new etcd registry
+func NewEtcd() *Etcd {
+	config := NewConfig()
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   config.GetEndpoints(),
+		DialTimeout: config.DialTimeout,
+		Username:    config.Username,
+		Password:    config.Password,
+	})
+
+	if err != nil {
+		panic(ErrCreatingEtcdClient)
+	}
+
+	e := &Etcd{
+		id:     fmt.Sprintf("%s-%s", config.ServiceName, utils.NewID()),
+		Client: client,
+		Config: config,
+	}
+
+	e.i = NewInitializer(e)
+	return e
+}
+
+// Register saves the service instance with a lease,
+// and keeps the lease alive until the instance is deregistered.
+func (e *Etcd) Register(ctx context.Context, server server.Server) error {
+	instance := &registry.Instance{
+		ID:      e.id,
+		Address: server.Host(),
+		Port:    server.Port(),
+		Tags:    server.RegistryTags(),
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.cancel != nil {
+		e.cancel()
+		<-e.done
+	}
+
+	kctx, cancel := context.WithCancel(context.Background())
+	keepAlive, err := e.put(ctx, kctx, instance)
+	if err != nil {
+		cancel()
+		e.logger.Errorw(
+			"Service registration failed",
+			"error", err.Error(),
+		)
+		return err
+	}
+
+	e.instance = instance
+	e.cancel = cancel
+	e.done = make(chan struct{})
+	go e.keepAlive(kctx, keepAlive)
+
+	e.logger.Infof("Successfully registered service: %s (%s)", e.ServiceName, e.id)
+	return nil
+}
+
+// put grants a new lease and saves the instance with it.
+// The lease is kept alive until kctx is done.
+func (e *Etcd) put(
+	ctx context.Context,
+	kctx context.Context,
+	instance *registry.Instance,
+) (<-chan *clientv3.LeaseKeepAliveResponse, error) {
+	value, err := json.Marshal(instance)
+	if err != nil {
+		return nil, fmt.Errorf("instance marshalling error: %v", err)
+	}
+
+	lease, err := e.Client.Grant(ctx, e.TTL)
+	if err != nil {
+		return nil, fmt.Errorf("lease grant error: %v", err)
+	}
+
+	if _, err := e.Client.Put(ctx, e.Key(), string(value), clientv3.WithLease(lease.ID)); err != nil {
+		return nil, fmt.Errorf("instance put error: %v", err)
+	}
+
+	keepAlive, err := e.Client.KeepAlive(kctx, lease.ID)
+	if err != nil {
+		return nil, fmt.Errorf("lease keep alive error: %v", err)
+	}
+
+	e.lease = lease.ID
+	return keepAlive, nil
+}
+
+// keepAlive consumes the keep alive responses of the lease.
+// The instance is registered again with a new lease if the lease is lost, e.g. after it expired.
+func (e *Etcd) keepAlive(ctx context.Context, keepAlive <-chan *clientv3.LeaseKeepAliveResponse) {
+	defer close(e.done)
+
+	for {
+		for range keepAlive {
+			// Drain the responses until the lease is lost
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		e.logger.Errorw("Service lease lost", "id", e.id)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(e.RetryBackoff):
+			}
+
+			var err error
+			if keepAlive, err = e.put(ctx, ctx, e.instance); err == nil {
+				e.logger.Infof("Successfully re-registered service: %s (%s)", e.ServiceName, e.id)
+				break
+			}
+
+			e.logger.Errorw("Service re-registration failed", "id", e.id, "error", err.Error())
+		}
+	}
+}
+
+// Deregister stops keeping the lease alive and revokes it, which removes the instance
+func (e *Etcd) Deregister(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.cancel == nil {
+		return nil
+	}
+
+	e.cancel()
+	<-e.done
+	e.cancel = nil
+
+	if _, err := e.Client.Revoke(ctx, e.lease); err != nil {
+		e.logger.Errorw(
+			"Service deregistration failed",
+			"error", err.Error(),
+		)
+		return err
+	}
+
+	e.logger.Infof("Successfully deregistered service: %s (%s)", e.ServiceName, e.id)
+	return nil
+}
+
+// Resolve returns the instances of the service registered with etcd
+func (e *Etcd) Resolve(ctx context.Context, name string) ([]*registry.Instance, error) {
+	res, err := e.Client.Get(ctx, e.ServicePrefix(name), clientv3.WithPrefix())
+	if err != nil {
+		return nil, fmt.Errorf("etcd resolve error: %v", err)
+	}
+
+	instances := make([]*registry.Instance, 0, len(res.Kvs))
+	for _, kv := range res.Kvs {
+		instance, err := decode(kv.Value)
+		if err != nil {
+			e.logger.Errorw("invalid instance", "key", string(kv.Key), "error", err)
+			continue
+		}
+
+		instances = append(instances, instance)
+	}
+
+	return instances, nil
+}
+
+// Watch watches the instances under the key prefix of the service
+func (e *Etcd) Watch(ctx context.Context, name string) (registry.Watcher, error) {
+	return newWatcher(ctx, e.Client, e.ServicePrefix(name)), nil
+}
+
+// ID returns the unique ID of the service instance
+func (e *Etcd) ID() string {
+	return e.id
+}
+
+// Key returns the key of the service instance
+func (e *Etcd) Key() string {
+	return e.ServicePrefix(e.ServiceName) + e.id
+}
+
+// ConnectionString returns the formatted connection string using the config loaded
+func (e *Etcd) ConnectionString(args ...interface{}) string {
+	return fmt.Sprintf("etcd://%s/%s", e.Address(), args[0])
+}
+
+// Address returns the address of the first etcd endpoint
+func (e *Etcd) Address() string {
+	endpoints := e.GetEndpoints()
+	if len(endpoints) == 0 {
+		return ""
+	}
+
+	return endpoints[0]
+}
+
+// ToString returns the string name of the service registry
+func (e *Etcd) ToString() string {
+	return "etcd"
+}
+
+func (e *Etcd) HasInitializer() bool {
+	return true
+}
+
+func (e *Etcd) Initializer() component.Initializer {
+	return e.i
+}
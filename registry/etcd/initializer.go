@@ -0,0 +1,60 @@
+package etcd
+
+import (
+	"context"
+
+	"github.com/easeq/go-service/logger"
+	"github.com/easeq/go-service/server"
+)
+
+type Initializer struct {
+	e *Etcd
+}
+
+// NewInitializer returns a new etcd registry Initialiazer
+func NewInitializer(e *Etcd) *Initializer {
+	return &Initializer{e}
+}
+
+// AddDependency adds necessary service components as dependencies
+func (i *Initializer) AddDependency(dep interface{}) error {
+	switch v := dep.(type) {
+	case logger.Logger:
+		i.e.logger = v
+	case server.Server:
+		i.e.server = v
+	}
+
+	return nil
+}
+
+// Dependencies returns the string names of service components
+// that are required as dependencies for this component
+func (i *Initializer) Dependencies() []string {
+	return []string{logger.LOGGER, server.SERVER}
+}
+
+// CanRun returns true if the component has anything to Run
+func (i *Initializer) CanRun() bool {
+	return true
+}
+
+// Run start the service component
+func (i *Initializer) Run(ctx context.Context) error {
+	i.e.logger.Infof("Registering service %s", i.e.ServiceName)
+	return i.e.Register(ctx, i.e.server)
+}
+
+// CanStop returns true if the component has anything to Stop
+func (i *Initializer) CanStop() bool {
+	return true
+}
+
+// Stop - revokes the lease of the service instance and closes the client
+func (i *Initializer) Stop(ctx context.Context) error {
+	if err := i.e.Deregister(ctx); err != nil {
+		return err
+	}
+
+	return i.e.Client.Close()
+}
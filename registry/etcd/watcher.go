@@ -0,0 +1,127 @@
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/easeq/go-service/registry"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// watcher watches the instances of a service under its key prefix
+type watcher struct {
+	ctx       context.Context
+	cancel    context.CancelFunc
+	client    *clientv3.Client
+	prefix    string
+	instances map[string]*registry.Instance
+	events    clientv3.WatchChan
+}
+
+func newWatcher(ctx context.Context, client *clientv3.Client, prefix string) *watcher {
+	ctx, cancel := context.WithCancel(ctx)
+	return &watcher{
+		ctx:       ctx,
+		cancel:    cancel,
+		client:    client,
+		prefix:    prefix,
+		instances: make(map[string]*registry.Instance),
+	}
+}
+
+// Next blocks until the instances of the service change and returns them
+func (w *watcher) Next() ([]*registry.Instance, error) {
+	if w.events == nil {
+		res, err := w.client.Get(w.ctx, w.prefix, clientv3.WithPrefix())
+		if err != nil {
+			return nil, w.err(err)
+		}
+
+		for _, kv := range res.Kvs {
+			w.put(string(kv.Key), kv.Value)
+		}
+
+		w.events = w.client.Watch(
+			w.ctx,
+			w.prefix,
+			clientv3.WithPrefix(),
+			clientv3.WithRev(res.Header.Revision+1),
+		)
+		return w.list(), nil
+	}
+
+	res, ok := <-w.events
+	if !ok {
+		return nil, registry.ErrWatcherStopped
+	}
+
+	if err := res.Err(); err != nil {
+		return nil, w.err(err)
+	}
+
+	w.apply(res.Events...)
+	return w.list(), nil
+}
+
+// Stop stops watching the service
+func (w *watcher) Stop() {
+	w.cancel()
+}
+
+// apply updates the instances with the watch events
+func (w *watcher) apply(events ...*clientv3.Event) {
+	for _, ev := range events {
+		switch ev.Type {
+		case clientv3.EventTypePut:
+			w.put(string(ev.Kv.Key), ev.Kv.Value)
+		case clientv3.EventTypeDelete:
+			delete(w.instances, string(ev.Kv.Key))
+		}
+	}
+}
+
+// put adds the instance saved in the key, invalid values are ignored
+func (w *watcher) put(key string, value []byte) {
+	instance, err := decode(value)
+	if err != nil {
+		return
+	}
+
+	w.instances[key] = instance
+}
+
+// list returns the instances sorted by ID
+func (w *watcher) list() []*registry.Instance {
+	instances := make([]*registry.Instance, 0, len(w.instances))
+	for _, instance := range w.instances {
+		instances = append(instances, instance)
+	}
+
+	sort.Slice(instances, func(i, j int) bool {
+		return instances[i].ID < instances[j].ID
+	})
+
+	return instances
+}
+
+// err returns ErrWatcherStopped once the watcher has been stopped
+func (w *watcher) err(err error) error {
+	if w.ctx.Err() != nil {
+		return registry.ErrWatcherStopped
+	}
+
+	return err
+}
+
+// decode decodes the instance saved in etcd.
+// The instance is healthy as long as its key exists, i.e. its lease is alive.
+func decode(value []byte) (*registry.Instance, error) {
+	instance := new(registry.Instance)
+	if err := json.Unmarshal(value, instance); err != nil {
+		return nil, err
+	}
+
+	instance.Health = registry.HEALTH_PASSING
+	return instance, nil
+}
@@ -0,0 +1,51 @@
+package etcd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/easeq/go-service/registry"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+func TestWatcherApply(t *testing.T) {
+	c := &Config{Prefix: "/go-service/registry/"}
+	prefix := c.ServicePrefix("orders")
+	require.Equal(t, "/go-service/registry/orders/", prefix)
+
+	w := newWatcher(context.Background(), nil, prefix)
+	w.apply(
+		&clientv3.Event{
+			Type: clientv3.EventTypePut,
+			Kv:   &mvccpb.KeyValue{Key: []byte(prefix + "b"), Value: []byte(`{"ID":"b","Address":"10.0.0.2","Port":8080}`)},
+		},
+		&clientv3.Event{
+			Type: clientv3.EventTypePut,
+			Kv:   &mvccpb.KeyValue{Key: []byte(prefix + "a"), Value: []byte(`{"ID":"a","Address":"10.0.0.1","Port":8080}`)},
+		},
+		&clientv3.Event{
+			Type: clientv3.EventTypePut,
+			Kv:   &mvccpb.KeyValue{Key: []byte(prefix + "invalid"), Value: []byte(`invalid`)},
+		},
+	)
+
+	require.Equal(t, []*registry.Instance{
+		{ID: "a", Address: "10.0.0.1", Port: 8080, Health: registry.HEALTH_PASSING},
+		{ID: "b", Address: "10.0.0.2", Port: 8080, Health: registry.HEALTH_PASSING},
+	}, w.list())
+
+	// The instance is removed once its lease expires
+	w.apply(&clientv3.Event{
+		Type: clientv3.EventTypeDelete,
+		Kv:   &mvccpb.KeyValue{Key: []byte(prefix + "a")},
+	})
+
+	require.Equal(t, []*registry.Instance{
+		{ID: "b", Address: "10.0.0.2", Port: 8080, Health: registry.HEALTH_PASSING},
+	}, w.list())
+
+	w.Stop()
+	require.ErrorIs(t, w.err(context.Canceled), registry.ErrWatcherStopped)
+}
@@ -2,6 +2,7 @@ package registry
 
 import (
 	"context"
+	"errors"
 
 	"github.com/easeq/go-service/component"
 	"github.com/easeq/go-service/server"
@@ -11,8 +12,60 @@ const (
 	REGISTRY = "registry"
 	// TAGS_SEPARATOR is the separator used to split the tags passed in the tag env var for the specific service registry.
 	TAGS_SEPARATOR = ","
+
+	// HEALTH_PASSING is the health of an instance passing all its checks
+	HEALTH_PASSING = "passing"
+	// HEALTH_WARNING is the health of an instance with a check in warning state
+	HEALTH_WARNING = "warning"
+	// HEALTH_CRITICAL is the health of an instance failing a check or under maintenance
+	HEALTH_CRITICAL = "critical"
+)
+
+var (
+	// ErrWatcherStopped returned by Watcher.Next once the watcher has been stopped
+	ErrWatcherStopped = errors.New("watcher stopped")
 )
 
+// Instance is a running instance of a service registered with the registry
+type Instance struct {
+	// ID is the unique ID of the instance in the registry
+	ID string
+	// Address is the host of the instance
+	Address string
+	// Port is the port the instance listens on
+	Port int
+	// Tags are the registry tags of the instance
+	Tags []string
+	// Health is the aggregated health of the instance checks
+	Health string
+}
+
+// Healthy returns true if the instance passes all its health checks
+func (i *Instance) Healthy() bool {
+	return i.Health == HEALTH_PASSING
+}
+
+// Healthy returns the instances passing all their health checks
+func Healthy(instances []*Instance) []*Instance {
+	healthy := make([]*Instance, 0, len(instances))
+	for _, instance := range instances {
+		if instance.Healthy() {
+			healthy = append(healthy, instance)
+		}
+	}
+
+	return healthy
+}
+
+// Watcher watches the instances of a service
+type Watcher interface {
+	// Next blocks until the instances of the service change and returns them.
+	// The first call returns the current instances.
+	Next() ([]*Instance, error)
+	// Stop stops watching the service
+	Stop()
+}
+
 // ServiceRegistry - service registry
 type ServiceRegistry interface {
 	component.Component
@@ -22,6 +75,10 @@ type ServiceRegistry interface {
 	Address() string
 	// ConnectionString returns the full formatted connection string
 	ConnectionString(...interface{}) string
+	// Resolve returns the instances of the service with the given name, along with their health
+	Resolve(ctx context.Context, name string) ([]*Instance, error)
+	// Watch watches the instances of the service with the given name
+	Watch(ctx context.Context, name string) (Watcher, error)
 	// Returns the string name of the registry
 	ToString() string
 }
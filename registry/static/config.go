@@ -0,0 +1,26 @@
+package static
+
+import (
+	"github.com/Netflix/go-env"
+	"github.com/easeq/go-service/component"
+)
+
+// Config holds the static registry configuration
+type Config struct {
+	// Services maps the service names to their addresses,
+	// e.g. "orders=10.0.0.1:8080,10.0.0.2:8080;users=localhost:9090"
+	Services string `env:"REGISTRY_STATIC_SERVICES"`
+}
+
+// NewConfig returns the parsed config for the static registry from env
+func NewConfig() *Config {
+	c := new(Config)
+	component.NewConfig(c)
+
+	return c
+}
+
+// UnmarshalEnv env.EnvSet to Config
+func (c *Config) UnmarshalEnv(es env.EnvSet) error {
+	return env.Unmarshal(es, c)
+}
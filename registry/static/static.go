@@ -0,0 +1,203 @@
+package static
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/easeq/go-service/component"
+	"github.com/easeq/go-service/logger"
+	"github.com/easeq/go-service/registry"
+	"github.com/easeq/go-service/server"
+)
+
+const (
+	// SERVICES_SEPARATOR separates the services in the services config
+	SERVICES_SEPARATOR = ";"
+	// ADDRESSES_SEPARATOR separates the addresses of a service in the services config
+	ADDRESSES_SEPARATOR = ","
+)
+
+var (
+	// ErrInvalidServices returned when the services config can't be parsed
+	ErrInvalidServices = errors.New("invalid static registry services")
+	// ErrServiceNotFound returned when resolving a service that isn't configured
+	ErrServiceNotFound = errors.New("service not found in the static registry")
+)
+
+// Option to pass as arg while creating new static registry
+type Option func(*Static)
+
+// Static registry resolves the services to the addresses configured.
+// It's meant for local development, where running a registry is not needed.
+type Static struct {
+	i        component.Initializer
+	logger   logger.Logger
+	services map[string][]*registry.Instance
+	*Config
+}
+
+// NewStatic returns a new static registry with the services from env
+func NewStatic(opts ...Option) *Static {
+	s := &Static{Config: NewConfig()}
+
+	services, err := Parse(s.Services)
+	if err != nil {
+		panic(err)
+	}
+
+	s.services = services
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.i = NewInitializer(s)
+	return s
+}
+
+// WithService sets the addresses (host:port) of the service
+func WithService(name string, addresses ...string) Option {
+	return func(s *Static) {
+		instances, err := toInstances(name, addresses)
+		if err != nil {
+			panic(err)
+		}
+
+		s.services[name] = instances
+	}
+}
+
+// Parse parses the services config into the instances of each service
+func Parse(services string) (map[string][]*registry.Instance, error) {
+	parsed := make(map[string][]*registry.Instance)
+	for _, service := range strings.Split(services, SERVICES_SEPARATOR) {
+		if service = strings.TrimSpace(service); service == "" {
+			continue
+		}
+
+		parts := strings.SplitN(service, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidServices, service)
+		}
+
+		name := strings.TrimSpace(parts[0])
+		instances, err := toInstances(name, strings.Split(parts[1], ADDRESSES_SEPARATOR))
+		if err != nil {
+			return nil, err
+		}
+
+		parsed[name] = instances
+	}
+
+	return parsed, nil
+}
+
+// toInstances converts the addresses of the service to registry instances
+func toInstances(name string, addresses []string) ([]*registry.Instance, error) {
+	instances := []*registry.Instance{}
+	for _, address := range addresses {
+		if address = strings.TrimSpace(address); address == "" {
+			continue
+		}
+
+		host, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidServices, err)
+		}
+
+		p, err := strconv.Atoi(port)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid port %s", ErrInvalidServices, port)
+		}
+
+		instances = append(instances, &registry.Instance{
+			ID:      fmt.Sprintf("%s-%s", name, address),
+			Address: host,
+			Port:    p,
+			Health:  registry.HEALTH_PASSING,
+		})
+	}
+
+	return instances, nil
+}
+
+// Register is a no-op, the services are configured statically
+func (s *Static) Register(ctx context.Context, server server.Server) error {
+	return nil
+}
+
+// Resolve returns the instances configured for the service
+func (s *Static) Resolve(ctx context.Context, name string) ([]*registry.Instance, error) {
+	instances, ok := s.services[name]
+	if !ok {
+		return nil, ErrServiceNotFound
+	}
+
+	return instances, nil
+}
+
+// Watch returns the instances configured for the service once, they never change
+func (s *Static) Watch(ctx context.Context, name string) (registry.Watcher, error) {
+	instances, err := s.Resolve(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	return &watcher{ctx, cancel, instances}, nil
+}
+
+// ConnectionString returns the address of the first instance of the service
+func (s *Static) ConnectionString(args ...interface{}) string {
+	instances := s.services[fmt.Sprint(args[0])]
+	if len(instances) == 0 {
+		return ""
+	}
+
+	return net.JoinHostPort(instances[0].Address, strconv.Itoa(instances[0].Port))
+}
+
+// Address returns an empty string, there's no registry to connect to
+func (s *Static) Address() string {
+	return ""
+}
+
+// ToString returns the string name of the service registry
+func (s *Static) ToString() string {
+	return "static"
+}
+
+func (s *Static) HasInitializer() bool {
+	return true
+}
+
+func (s *Static) Initializer() component.Initializer {
+	return s.i
+}
+
+// watcher returns the static instances on the first call and blocks afterwards
+type watcher struct {
+	ctx       context.Context
+	cancel    context.CancelFunc
+	instances []*registry.Instance
+}
+
+// Next returns the instances on the first call, and blocks until stopped afterwards
+func (w *watcher) Next() ([]*registry.Instance, error) {
+	if w.instances != nil {
+		instances := w.instances
+		w.instances = nil
+		return instances, nil
+	}
+
+	<-w.ctx.Done()
+	return nil, registry.ErrWatcherStopped
+}
+
+// Stop stops watching the service
+func (w *watcher) Stop() {
+	w.cancel()
+}
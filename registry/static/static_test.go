@@ -0,0 +1,79 @@
+package static
+
+import (
+	"context"
+	"testing"
+
+	"github.com/easeq/go-service/registry"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		services string
+		want     map[string][]*registry.Instance
+		wantErr  error
+	}{
+		{
+			name:     "empty",
+			services: "",
+			want:     map[string][]*registry.Instance{},
+		},
+		{
+			name:     "multipleServices",
+			services: "orders=10.0.0.1:8080, 10.0.0.2:8080; users=localhost:9090",
+			want: map[string][]*registry.Instance{
+				"orders": {
+					{ID: "orders-10.0.0.1:8080", Address: "10.0.0.1", Port: 8080, Health: registry.HEALTH_PASSING},
+					{ID: "orders-10.0.0.2:8080", Address: "10.0.0.2", Port: 8080, Health: registry.HEALTH_PASSING},
+				},
+				"users": {
+					{ID: "users-localhost:9090", Address: "localhost", Port: 9090, Health: registry.HEALTH_PASSING},
+				},
+			},
+		},
+		{
+			name:     "missingAddresses",
+			services: "orders",
+			wantErr:  ErrInvalidServices,
+		},
+		{
+			name:     "invalidPort",
+			services: "orders=localhost:port",
+			wantErr:  ErrInvalidServices,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.services)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWatch(t *testing.T) {
+	s := NewStatic(WithService("orders", "localhost:8080"))
+
+	_, err := s.Watch(context.Background(), "users")
+	require.ErrorIs(t, err, ErrServiceNotFound)
+
+	w, err := s.Watch(context.Background(), "orders")
+	require.NoError(t, err)
+
+	instances, err := w.Next()
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+	require.Equal(t, "localhost:8080", s.ConnectionString("orders"))
+
+	w.Stop()
+	_, err = w.Next()
+	require.ErrorIs(t, err, registry.ErrWatcherStopped)
+}
@@ -10,10 +10,13 @@ import (
 
 // Config manages the HTTP server config
 type Config struct {
-	Host     string `env:"HTTP_HOST,defaut="`
-	Port     int    `env:"HTTP_PORT,default=8080"`
-	Tags     string `env:"HTTP_CONSUL_TAGS,default="`
-	Metadata Metadata
+	Host        string `env:"HTTP_HOST,defaut="`
+	Port        int    `env:"HTTP_PORT,default=8080"`
+	Tags        string `env:"HTTP_CONSUL_TAGS,default="`
+	OpenAPIPath string `env:"HTTP_OPENAPI_PATH,default=/openapi.json"`
+	DocsPath    string `env:"HTTP_DOCS_PATH,default=/docs"`
+	DocsUIURL   string `env:"HTTP_DOCS_UI_URL,default=https://unpkg.com/swagger-ui-dist@5"`
+	Metadata    Metadata
 }
 
 // NewConfig returns the parsed config for gateway server from env
@@ -0,0 +1,122 @@
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+)
+
+var docsTemplate = template.Must(template.New("docs").Parse(`<!DOCTYPE html>
+<html>
+<head>
+	<meta charset="utf-8">
+	<title>API documentation</title>
+	<link rel="stylesheet" href="{{.UIURL}}/swagger-ui.css">
+</head>
+<body>
+	<div id="swagger-ui"></div>
+	<script src="{{.UIURL}}/swagger-ui-bundle.js"></script>
+	<script>
+		window.onload = function() {
+			SwaggerUIBundle({url: "{{.SpecPath}}", dom_id: "#swagger-ui"});
+		};
+	</script>
+</body>
+</html>
+`))
+
+// WithOpenAPI serves the OpenAPI documents generated by protoc-gen-go-service, merged into one,
+// and the docs UI rendering them
+func WithOpenAPI(specs ...[]byte) Option {
+	return func(g *Gateway) {
+		g.OpenAPISpecs = append(g.OpenAPISpecs, specs...)
+	}
+}
+
+// handleDocs registers the handlers of the OpenAPI document and of the docs UI.
+// It returns ErrInvalidOpenAPISpec if the documents can't be merged.
+func (g *Gateway) handleDocs() error {
+	spec, err := mergeSpecs(g.OpenAPISpecs...)
+	if err != nil {
+		return err
+	}
+
+	// The docs page only depends on the config, it's rendered once
+	var page bytes.Buffer
+	if err := docsTemplate.Execute(&page, struct {
+		UIURL    string
+		SpecPath string
+	}{g.Config.DocsUIURL, g.Config.OpenAPIPath}); err != nil {
+		return fmt.Errorf("docs page: %v", err)
+	}
+
+	err = g.Mux.HandlePath(http.MethodGet, g.Config.OpenAPIPath, func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(spec)
+	})
+	if err != nil {
+		return err
+	}
+
+	return g.Mux.HandlePath(http.MethodGet, g.Config.DocsPath, func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(page.Bytes())
+	})
+}
+
+// mergeSpecs merges the paths, tags and components of the OpenAPI documents into the first one
+func mergeSpecs(specs ...[]byte) ([]byte, error) {
+	var merged map[string]interface{}
+	for i, spec := range specs {
+		doc := map[string]interface{}{}
+		if err := json.Unmarshal(spec, &doc); err != nil {
+			return nil, fmt.Errorf("%w: document %d: %v", ErrInvalidOpenAPISpec, i, err)
+		}
+
+		if merged == nil {
+			merged = doc
+			continue
+		}
+
+		mergeObjects(merged, doc, "paths")
+		if tags, ok := doc["tags"].([]interface{}); ok {
+			existing, _ := merged["tags"].([]interface{})
+			merged["tags"] = append(existing, tags...)
+		}
+
+		components, ok := doc["components"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if _, ok := merged["components"].(map[string]interface{}); !ok {
+			merged["components"] = map[string]interface{}{}
+		}
+
+		for key := range components {
+			mergeObjects(merged["components"].(map[string]interface{}), components, key)
+		}
+	}
+
+	return json.Marshal(merged)
+}
+
+// mergeObjects adds the entries of the object at key in src to the object at key in dst
+func mergeObjects(dst map[string]interface{}, src map[string]interface{}, key string) {
+	from, ok := src[key].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	to, ok := dst[key].(map[string]interface{})
+	if !ok {
+		to = map[string]interface{}{}
+		dst[key] = to
+	}
+
+	for k, v := range from {
+		to[k] = v
+	}
+}
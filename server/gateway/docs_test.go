@@ -0,0 +1,84 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/easeq/go-service/logger"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	booksSpec = `{
+		"openapi": "3.0.3",
+		"info": {"title": "books.proto", "version": "1.0.0"},
+		"tags": [{"name": "Books"}],
+		"paths": {"/v1/books": {"get": {"operationId": "Books_ListBooks"}}},
+		"components": {"schemas": {"books.Book": {"type": "object"}}}
+	}`
+	shelvesSpec = `{
+		"openapi": "3.0.3",
+		"info": {"title": "shelves.proto", "version": "1.0.0"},
+		"tags": [{"name": "Shelves"}],
+		"paths": {"/v1/shelves": {"get": {"operationId": "Shelves_ListShelves"}}},
+		"components": {"schemas": {"shelves.Shelf": {"type": "object"}}}
+	}`
+)
+
+func serve(t *testing.T, g *Gateway, path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	g.Server.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+	return rec
+}
+
+func TestDocs(t *testing.T) {
+	g := NewGateway(WithMiddleware(), WithOpenAPI([]byte(booksSpec), []byte(shelvesSpec)))
+	require.NoError(t, g.handleDocs())
+
+	rec := serve(t, g, g.Config.OpenAPIPath)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	doc := struct {
+		Info       map[string]string
+		Tags       []map[string]string
+		Paths      map[string]interface{}
+		Components struct{ Schemas map[string]interface{} }
+	}{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+	require.Equal(t, "books.proto", doc.Info["title"])
+	require.Len(t, doc.Tags, 2)
+	require.Contains(t, doc.Paths, "/v1/books")
+	require.Contains(t, doc.Paths, "/v1/shelves")
+	require.Contains(t, doc.Components.Schemas, "books.Book")
+	require.Contains(t, doc.Components.Schemas, "shelves.Shelf")
+
+	rec = serve(t, g, g.Config.DocsPath)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), g.Config.DocsUIURL+"/swagger-ui-bundle.js")
+	require.Contains(t, rec.Body.String(), "openapi.json")
+}
+
+func TestDocsInvalidSpec(t *testing.T) {
+	_, err := mergeSpecs([]byte(booksSpec), []byte("{"))
+	require.ErrorIs(t, err, ErrInvalidOpenAPISpec)
+
+	g := NewGateway(WithMiddleware(), WithOpenAPI([]byte("{")))
+	require.ErrorIs(t, g.handleDocs(), ErrInvalidOpenAPISpec)
+
+	// The gateway fails to start instead of serving an error
+	g.logger = nopLogger{}
+	require.ErrorIs(t, NewInitializer(g).Run(context.Background()), ErrDocsRegFailed)
+}
+
+type nopLogger struct {
+	logger.Logger
+}
+
+func (nopLogger) Infow(message string, args ...interface{}) {}
+
+func (nopLogger) Errorw(message string, args ...interface{}) {}
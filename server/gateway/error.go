@@ -0,0 +1,24 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	gs_errors "github.com/easeq/go-service/errors"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+)
+
+// ErrorHandler writes the errors of the gateway as JSON errors.Response bodies.
+// The errors returned by the gRPC services are decoded from their status details,
+// the internal errors are masked.
+func ErrorHandler(ctx context.Context, mux *runtime.ServeMux, marshaler runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+	var httpStatus int
+	var statusErr *runtime.HTTPStatusError
+	if errors.As(err, &statusErr) {
+		httpStatus = statusErr.HTTPStatus
+		err = statusErr.Err
+	}
+
+	gs_errors.WriteHTTP(w, err, httpStatus)
+}
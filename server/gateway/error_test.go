@@ -0,0 +1,66 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	gs_errors "github.com/easeq/go-service/errors"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorHandler(t *testing.T) {
+	g := NewGateway(WithMiddleware())
+	err := g.Mux.HandlePath(http.MethodGet, "/v1/books/{name}", func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+		// The handlers generated by grpc-gateway pass the client errors to the error handler
+		var err error = status.Convert(gs_errors.Validation("invalid book", gs_errors.Violation("name", "unknown book"))).Err()
+		if params["name"] == "internal" {
+			err = errors.New("pq: connection reset by peer")
+		}
+
+		runtime.HTTPError(r.Context(), g.Mux, &runtime.JSONPb{}, w, r, err)
+	})
+	require.NoError(t, err)
+
+	rec := serve(t, g, "/v1/books/unknown")
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	res := gs_errors.Response{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	require.Equal(t, int32(codes.InvalidArgument), res.Code)
+	require.Equal(t, []gs_errors.FieldViolation{gs_errors.Violation("name", "unknown book")}, res.Violations)
+
+	rec = serve(t, g, "/v1/books/internal")
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+	require.NotContains(t, rec.Body.String(), "pq:")
+
+	// The routing errors keep their HTTP status
+	rec = httptest.NewRecorder()
+	ErrorHandler(context.Background(), g.Mux, &runtime.JSONPb{}, rec, httptest.NewRequest(http.MethodPost, "/v1/books/unknown", nil), &runtime.HTTPStatusError{
+		HTTPStatus: http.StatusMethodNotAllowed,
+		Err:        status.Error(codes.Unimplemented, http.StatusText(http.StatusMethodNotAllowed)),
+	})
+	require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	require.Equal(t, int32(codes.Unimplemented), res.Code)
+}
+
+func TestErrorHandlerOverride(t *testing.T) {
+	called := false
+	g := NewGateway(WithMiddleware(), WithMuxOptions(runtime.WithErrorHandler(
+		func(ctx context.Context, mux *runtime.ServeMux, m runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+			called = true
+			w.WriteHeader(http.StatusTeapot)
+		},
+	)))
+
+	rec := serve(t, g, "/missing")
+	require.True(t, called)
+	require.Equal(t, http.StatusTeapot, rec.Code)
+}
@@ -23,6 +23,10 @@ var (
 	ErrHTTPServiceHandlerRegFailed = errors.New("http service handler registration failed")
 	// ErrCannotAddMuxOptionAtPos returned when adding new mux option at the specified position is not possible
 	ErrCannotAddMuxOptionAtPos = errors.New("cannot add mux option at the position specified")
+	// ErrInvalidOpenAPISpec returned when an OpenAPI document served isn't valid JSON
+	ErrInvalidOpenAPISpec = errors.New("invalid OpenAPI document")
+	// ErrDocsRegFailed returned when the OpenAPI document and docs UI handlers registration fails
+	ErrDocsRegFailed = errors.New("docs handler registration failed")
 )
 
 const (
@@ -47,6 +51,7 @@ type Gateway struct {
 	Middleware                  []Middleware
 	HTTPServiceHandlerRegistrar HTTPServiceHandlerRegistrar
 	MuxOptions                  []runtime.ServeMuxOption
+	OpenAPISpecs                [][]byte
 	Server                      *http.Server
 	exit                        chan os.Signal
 	*Config
@@ -65,7 +70,9 @@ func NewGateway(opts ...Option) *Gateway {
 		opt(g)
 	}
 
-	g.Mux = runtime.NewServeMux(g.MuxOptions...)
+	// The mux options given replace the default error handler
+	muxOptions := append([]runtime.ServeMuxOption{runtime.WithErrorHandler(ErrorHandler)}, g.MuxOptions...)
+	g.Mux = runtime.NewServeMux(muxOptions...)
 	g.Server = &http.Server{
 		Addr:    g.Address(),
 		Handler: chainMiddleware(g.Mux, g.Middleware...),
@@ -2,6 +2,7 @@ package gateway
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/easeq/go-service/logger"
 )
@@ -41,6 +42,21 @@ func (i *Initializer) Run(ctx context.Context) error {
 	i.g.logger.Infow(
 		"Starting HTTP/REST gRPC gateway...",
 	)
+
+	if i.g.HTTPServiceHandlerRegistrar != nil {
+		if err := i.g.HTTPServiceHandlerRegistrar(ctx, i.g); err != nil {
+			i.g.logger.Errorw("http service handler registration error", "err", err)
+			return fmt.Errorf("%w: %v", ErrHTTPServiceHandlerRegFailed, err)
+		}
+	}
+
+	if len(i.g.OpenAPISpecs) > 0 {
+		if err := i.g.handleDocs(); err != nil {
+			i.g.logger.Errorw("docs handler registration error", "err", err)
+			return fmt.Errorf("%w: %v", ErrDocsRegFailed, err)
+		}
+	}
+
 	return i.g.Server.ListenAndServe()
 }
 
@@ -13,6 +13,12 @@ type Config struct {
 	Host string `env:"GRPC_HOST,defaut="`
 	Port int    `env:"GRPC_PORT,default=9090"`
 	Tags string `env:"GRPC_CONSUL_TAGS,default="`
+
+	Tracing    bool `env:"GRPC_SERVER_TRACING,default=true"`
+	Logging    bool `env:"GRPC_SERVER_LOGGING,default=true"`
+	Recovery   bool `env:"GRPC_SERVER_RECOVERY,default=true"`
+	Validation bool `env:"GRPC_SERVER_VALIDATION,default=true"`
+	Metrics    bool `env:"GRPC_SERVER_METRICS,default=true"`
 }
 
 // NewConfig returns the parsed config for jetstream from env
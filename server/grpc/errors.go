@@ -0,0 +1,86 @@
+package grpc
+
+import (
+	"context"
+
+	gs_errors "github.com/easeq/go-service/errors"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// WithErrorMappers adds the mappers of the errors returned by the handlers,
+// used before the default mappings of errors.FromError
+func WithErrorMappers(mappers ...gs_errors.Mapper) Option {
+	return func(g *Grpc) {
+		g.errorRegistry.Register(mappers...)
+	}
+}
+
+// RegisterErrorMappers adds the mappers of the errors returned by the handlers
+func (g *Grpc) RegisterErrorMappers(mappers ...gs_errors.Mapper) {
+	g.errorRegistry.Register(mappers...)
+}
+
+// UnaryErrorInterceptor converts the errors returned by the handlers to status errors
+func (g *Grpc) UnaryErrorInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, g.translateError(ctx, info.FullMethod, err)
+		}
+
+		return resp, nil
+	}
+}
+
+// StreamErrorInterceptor converts the errors returned by the stream handlers to status errors
+func (g *Grpc) StreamErrorInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := handler(srv, ss); err != nil {
+			return g.translateError(ss.Context(), info.FullMethod, err)
+		}
+
+		return nil
+	}
+}
+
+// unexpectedError is the status error of an unexpected handler error.
+// Only the status is sent to the client, the handler error is kept for the logging interceptor.
+type unexpectedError struct {
+	status *status.Status
+	err    error
+}
+
+// Error returns the error of the status
+func (e *unexpectedError) Error() string {
+	return e.status.Err().Error()
+}
+
+// GRPCStatus returns the status sent to the client
+func (e *unexpectedError) GRPCStatus() *status.Status {
+	return e.status
+}
+
+// translateError returns the status error of err, only the sanitized message of the unexpected errors is returned.
+// The unexpected errors are logged once with the trace ID of the call,
+// by the logging interceptor when the calls are logged.
+func (g *Grpc) translateError(ctx context.Context, method string, err error) error {
+	e := g.errorRegistry.FromError(err)
+	switch e.Code {
+	case codes.Unknown, codes.Internal, codes.DataLoss:
+		if g.logger != nil && !g.Config.Logging {
+			kv := []interface{}{"method", method, "code", e.Code.String()}
+			if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+				kv = append(kv, "trace_id", sc.TraceID().String())
+			}
+
+			g.logger.Errorw("unexpected handler error", append(kv, "err", err)...)
+		}
+
+		return &unexpectedError{status: e.GRPCStatus(), err: err}
+	}
+
+	return e.GRPCStatus().Err()
+}
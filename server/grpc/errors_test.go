@@ -0,0 +1,120 @@
+package grpc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	gs_errors "github.com/easeq/go-service/errors"
+	"github.com/easeq/go-service/logger"
+	grpc_error "github.com/easeq/go-service/server/grpc/error"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+var errShelfFull = errors.New("shelf is full")
+
+// recordLogger records the key-value pairs of the errors logged
+type recordLogger struct {
+	logger.Logger
+	errors chan []interface{}
+}
+
+func (l *recordLogger) Errorw(message string, args ...interface{}) {
+	l.errors <- args
+}
+
+// errorServer returns the error of the service of the request
+type errorServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+}
+
+func (s *errorServer) Check(ctx context.Context, req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	switch req.Service {
+	case "missing":
+		return nil, fmt.Errorf("loading book: %w", sql.ErrNoRows)
+	case "full":
+		return nil, fmt.Errorf("adding book: %w", errShelfFull)
+	case "invalid":
+		return nil, gs_errors.Validation("invalid book", gs_errors.Violation("title", "must not be empty"))
+	case "canceled":
+		return nil, context.Canceled
+	}
+
+	return nil, errors.New("pq: password authentication failed for user books")
+}
+
+func TestErrorInterceptor(t *testing.T) {
+	g := NewGrpc(WithLogging(false), WithErrorMappers(gs_errors.Map(errShelfFull, codes.FailedPrecondition, "shelf is full")))
+	log := &recordLogger{errors: make(chan []interface{}, 1)}
+	g.logger = log
+
+	hc := startServer(t, g, &errorServer{})
+
+	tests := []struct {
+		service string
+		code    codes.Code
+		message string
+	}{
+		{"missing", codes.NotFound, "not found"},
+		{"full", codes.FailedPrecondition, "shelf is full"},
+		{"invalid", codes.InvalidArgument, "invalid book"},
+		{"canceled", codes.Canceled, "canceled"},
+		{"unexpected", codes.Internal, gs_errors.INTERNAL_MESSAGE},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.service, func(t *testing.T) {
+			_, err := hc.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: tt.service})
+			st := status.Convert(err)
+			require.Equal(t, tt.code, st.Code())
+			require.Equal(t, tt.message, st.Message())
+			require.Len(t, st.Details(), 1)
+			require.True(t, st.Details()[0].(*grpc_error.ErrorDetail).Public)
+		})
+	}
+
+	// Only the unexpected error is logged, with its details
+	logged := <-log.errors
+	require.Contains(t, logged, "err")
+	require.Contains(t, fmt.Sprint(logged...), "pq: password authentication failed")
+	require.Empty(t, log.errors)
+}
+
+func TestErrorInterceptorTraceID(t *testing.T) {
+	g := NewGrpc(WithLogging(false))
+	log := &recordLogger{errors: make(chan []interface{}, 1)}
+	g.logger = log
+
+	traceID := trace.TraceID{1, 2, 3}
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  trace.SpanID{1},
+	}))
+
+	err := g.translateError(ctx, checkMethod, errors.New("unexpected"))
+	require.Equal(t, codes.Internal, status.Code(err))
+	require.Contains(t, <-log.errors, traceID.String())
+}
+
+func TestErrorInterceptorLogging(t *testing.T) {
+	g := NewGrpc()
+	log := &recordLogger{errors: make(chan []interface{}, 2)}
+	g.logger = log
+
+	hc := startServer(t, g, &errorServer{})
+
+	_, err := hc.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "unexpected"})
+	require.Equal(t, gs_errors.INTERNAL_MESSAGE, status.Convert(err).Message())
+
+	// The unexpected error is logged once by the logging interceptor, with its details
+	logged := <-log.errors
+	require.Contains(t, logged, codes.Internal.String())
+	require.Contains(t, fmt.Sprint(logged...), "pq: password authentication failed")
+	require.Empty(t, log.errors)
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/easeq/go-service/component"
+	gs_errors "github.com/easeq/go-service/errors"
 	"github.com/easeq/go-service/logger"
 	"github.com/easeq/go-service/registry"
 
@@ -26,12 +27,19 @@ const (
 
 // Grpc holds gRPC config
 type Grpc struct {
-	i             component.Initializer
-	logger        logger.Logger
-	ServerOptions []grpc.ServerOption
-	DialOptions   []grpc.DialOption
-	Server        *grpc.Server
-	exit          chan os.Signal
+	i                  component.Initializer
+	logger             logger.Logger
+	ServerOptions      []grpc.ServerOption
+	DialOptions        []grpc.DialOption
+	Server             *grpc.Server
+	exit               chan os.Signal
+	methods            methods
+	authenticator      Authenticator
+	errorRegistry      *gs_errors.Registry
+	stats              stats
+	recorders          []MetricsRecorder
+	unaryInterceptors  []grpc.UnaryServerInterceptor
+	streamInterceptors []grpc.StreamServerInterceptor
 	*Config
 }
 
@@ -45,13 +53,23 @@ func NewGrpc(opts ...Option) *Grpc {
 		ServerOptions: []grpc.ServerOption{},
 		Config:        NewConfig(),
 		exit:          make(chan os.Signal),
+		methods:       methods{options: map[string]MethodOptions{}},
+		errorRegistry: gs_errors.NewRegistry(),
+		stats:         stats{methods: map[string]*MethodStats{}},
 	}
 
 	for _, opt := range opts {
 		opt(g)
 	}
 
-	g.Server = grpc.NewServer(g.ServerOptions...)
+	// The built-in interceptors are run after the interceptors of the server options
+	unary, stream := g.interceptors()
+	serverOpts := append(
+		append([]grpc.ServerOption{}, g.ServerOptions...),
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	)
+	g.Server = grpc.NewServer(serverOpts...)
 	g.i = NewInitializer(g)
 
 	return g
@@ -60,7 +78,7 @@ func NewGrpc(opts ...Option) *Grpc {
 // WithGrpcServerOptions adds gRPC options
 func WithGrpcServerOptions(opts ...grpc.ServerOption) Option {
 	return func(g *Grpc) {
-		g.ServerOptions = opts
+		g.ServerOptions = append(g.ServerOptions, opts...)
 	}
 }
 
@@ -0,0 +1,346 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	gs_errors "github.com/easeq/go-service/errors"
+	"github.com/easeq/go-service/tracer"
+	"go.opentelemetry.io/otel"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// WithUnaryInterceptors adds unary interceptors, run in order after the built-in interceptors
+func WithUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) Option {
+	return func(g *Grpc) {
+		g.unaryInterceptors = append(g.unaryInterceptors, interceptors...)
+	}
+}
+
+// WithStreamInterceptors adds stream interceptors, run in order after the built-in interceptors
+func WithStreamInterceptors(interceptors ...grpc.StreamServerInterceptor) Option {
+	return func(g *Grpc) {
+		g.streamInterceptors = append(g.streamInterceptors, interceptors...)
+	}
+}
+
+// WithTracing turns the server spans of the calls on or off
+func WithTracing(enabled bool) Option {
+	return func(g *Grpc) {
+		g.Config.Tracing = enabled
+	}
+}
+
+// WithLogging turns the logging of the calls on or off
+func WithLogging(enabled bool) Option {
+	return func(g *Grpc) {
+		g.Config.Logging = enabled
+	}
+}
+
+// WithRecovery turns the recovery of the handler panics on or off
+func WithRecovery(enabled bool) Option {
+	return func(g *Grpc) {
+		g.Config.Recovery = enabled
+	}
+}
+
+// WithValidation turns the validation of the requests on or off
+func WithValidation(enabled bool) Option {
+	return func(g *Grpc) {
+		g.Config.Validation = enabled
+	}
+}
+
+// WithMetrics turns the metrics of the calls on or off
+func WithMetrics(enabled bool) Option {
+	return func(g *Grpc) {
+		g.Config.Metrics = enabled
+	}
+}
+
+// interceptors returns the interceptor chains of the server. The built-in interceptors
+// tracing, metrics, logging, error translation, recovery, method options and validation
+// are run first, each one enabled by the config, followed by the interceptors added.
+func (g *Grpc) interceptors() ([]grpc.UnaryServerInterceptor, []grpc.StreamServerInterceptor) {
+	var unary []grpc.UnaryServerInterceptor
+	var stream []grpc.StreamServerInterceptor
+
+	if g.Config.Tracing {
+		unary = append(unary, TracingUnaryServerInterceptor())
+		stream = append(stream, TracingStreamServerInterceptor())
+	}
+
+	if g.Config.Metrics {
+		unary = append(unary, g.MetricsUnaryServerInterceptor())
+		stream = append(stream, g.MetricsStreamServerInterceptor())
+	}
+
+	if g.Config.Logging {
+		unary = append(unary, g.LoggingUnaryServerInterceptor())
+		stream = append(stream, g.LoggingStreamServerInterceptor())
+	}
+
+	unary = append(unary, g.UnaryErrorInterceptor())
+	stream = append(stream, g.StreamErrorInterceptor())
+
+	if g.Config.Recovery {
+		unary = append(unary, RecoveryUnaryServerInterceptor())
+		stream = append(stream, RecoveryStreamServerInterceptor())
+	}
+
+	unary = append(unary, g.UnaryMethodOptionsInterceptor())
+	stream = append(stream, g.StreamMethodOptionsInterceptor())
+
+	if g.Config.Validation {
+		unary = append(unary, ValidationUnaryServerInterceptor())
+		stream = append(stream, ValidationStreamServerInterceptor())
+	}
+
+	return append(unary, g.unaryInterceptors...), append(stream, g.streamInterceptors...)
+}
+
+// metadataCarrier adapts the incoming metadata to propagation.TextMapCarrier
+type metadataCarrier struct {
+	md metadata.MD
+}
+
+// Get returns the first value of the key
+func (mc metadataCarrier) Get(key string) string {
+	values := mc.md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+// Set sets the value of the key
+func (mc metadataCarrier) Set(key string, value string) {
+	mc.md.Set(key, value)
+}
+
+// Keys returns the metadata keys
+func (mc metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(mc.md))
+	for k := range mc.md {
+		keys = append(keys, k)
+	}
+
+	return keys
+}
+
+// splitMethod splits the full method name /package.Service/Method into service and method
+func splitMethod(method string) (string, string) {
+	method = strings.TrimPrefix(method, "/")
+	if i := strings.LastIndex(method, "/"); i >= 0 {
+		return method[:i], method[i+1:]
+	}
+
+	return "", method
+}
+
+// startSpan starts the server span of the call, child of the span propagated by the client
+func startSpan(ctx context.Context, method string) (context.Context, trace.Span) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier{md})
+	}
+
+	service, name := splitMethod(method)
+	return otel.GetTracerProvider().Tracer(tracer.DEFAULT_TRACER_NAME).Start(
+		ctx,
+		strings.TrimPrefix(method, "/"),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			semconv.RPCSystemKey.String("grpc"),
+			semconv.RPCServiceKey.String(service),
+			semconv.RPCMethodKey.String(name),
+		),
+	)
+}
+
+// endSpan records the status of the call and ends the span
+func endSpan(span trace.Span, err error) {
+	s, _ := status.FromError(err)
+	span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(s.Code())))
+	if err != nil {
+		span.SetStatus(otelcodes.Error, s.Message())
+	}
+
+	span.End()
+}
+
+// TracingUnaryServerInterceptor starts a server span for the call
+func TracingUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, span := startSpan(ctx, info.FullMethod)
+		resp, err := handler(ctx, req)
+		endSpan(span, err)
+		return resp, err
+	}
+}
+
+// TracingStreamServerInterceptor starts a server span for the stream
+func TracingStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, span := startSpan(ss.Context(), info.FullMethod)
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		endSpan(span, err)
+		return err
+	}
+}
+
+// LoggingUnaryServerInterceptor logs the calls and their errors
+func (g *Grpc) LoggingUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		g.log(ctx, "call", info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// LoggingStreamServerInterceptor logs the streams and their errors
+func (g *Grpc) LoggingStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		g.log(ss.Context(), "stream", info.FullMethod, start, err)
+		return err
+	}
+}
+
+// log logs the call with its trace ID, the failed calls are logged as errors
+func (g *Grpc) log(ctx context.Context, msg string, method string, start time.Time, err error) {
+	if g.logger == nil {
+		return
+	}
+
+	kv := []interface{}{
+		"method", method,
+		"code", status.Code(err).String(),
+		"duration", time.Since(start),
+	}
+
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		kv = append(kv, "trace_id", sc.TraceID().String())
+	}
+
+	// The unexpected errors are logged with the handler error instead of the status sent to the client
+	var unexpected *unexpectedError
+	if errors.As(err, &unexpected) {
+		g.logger.Errorw(msg+" error", append(kv, "err", unexpected.err)...)
+		return
+	}
+
+	if err != nil {
+		g.logger.Errorw(msg+" error", append(kv, "err", err)...)
+		return
+	}
+
+	g.logger.Debugw(msg, kv...)
+}
+
+// recoverError returns the internal error of the recovered panic, with its stack trace
+func recoverError(p interface{}) error {
+	return gs_errors.Internal(fmt.Errorf("panic: %v\n%s", p, debug.Stack()))
+}
+
+// RecoveryUnaryServerInterceptor converts the panics of the handlers to internal errors
+func RecoveryUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = recoverError(p)
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+// RecoveryStreamServerInterceptor converts the panics of the stream handlers to internal errors
+func RecoveryStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = recoverError(p)
+			}
+		}()
+
+		return handler(srv, ss)
+	}
+}
+
+// validator is a request validated by the Validate method, e.g. generated by protoc-gen-validate
+type validator interface {
+	Validate() error
+}
+
+// allValidator is a request validated by the ValidateAll method, returning all the violations
+type allValidator interface {
+	ValidateAll() error
+}
+
+// validate validates the request, the errors are returned as invalid argument errors
+func validate(req interface{}) error {
+	var err error
+	switch v := req.(type) {
+	case allValidator:
+		err = v.ValidateAll()
+	case validator:
+		err = v.Validate()
+	}
+
+	if err == nil {
+		return nil
+	}
+
+	if e := gs_errors.FromError(err); e.Code == codes.InvalidArgument {
+		return e
+	}
+
+	return gs_errors.Wrap(err, codes.InvalidArgument, err.Error())
+}
+
+// ValidationUnaryServerInterceptor validates the requests implementing Validate or ValidateAll
+func ValidationUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := validate(req); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// ValidationStreamServerInterceptor validates the messages received on the streams
+func ValidationStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &validatedStream{ss})
+	}
+}
+
+// validatedStream validates the messages received
+type validatedStream struct {
+	grpc.ServerStream
+}
+
+// RecvMsg receives a message and validates it
+func (s *validatedStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	return validate(m)
+}
@@ -0,0 +1,181 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	gs_errors "github.com/easeq/go-service/errors"
+	"github.com/easeq/go-service/logger"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// callLogger records the messages logged
+type callLogger struct {
+	logger.Logger
+	messages chan string
+}
+
+func (l *callLogger) Debugw(message string, args ...interface{}) {
+	l.messages <- message
+}
+
+func (l *callLogger) Errorw(message string, args ...interface{}) {
+	l.messages <- message
+}
+
+// panicServer panics on the checks of the service "panic"
+type panicServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+}
+
+func (s *panicServer) Check(ctx context.Context, req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	if req.Service == "panic" {
+		panic("nil map")
+	}
+
+	return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
+}
+
+// book is a request validated by ValidateAll
+type book struct {
+	title string
+}
+
+func (b *book) ValidateAll() error {
+	if b.title == "" {
+		return errors.New("title must not be empty")
+	}
+
+	return nil
+}
+
+func TestRecovery(t *testing.T) {
+	g := NewGrpc(WithLogging(false))
+	log := &callLogger{messages: make(chan string, 1)}
+	g.logger = log
+
+	hc := startServer(t, g, &panicServer{})
+
+	_, err := hc.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "panic"})
+	require.Equal(t, codes.Internal, status.Code(err))
+	require.Equal(t, gs_errors.INTERNAL_MESSAGE, status.Convert(err).Message())
+	require.Equal(t, "unexpected handler error", <-log.messages)
+
+	// The server keeps serving after the panic
+	_, err = hc.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	require.NoError(t, err)
+}
+
+func TestLoggingAndMetrics(t *testing.T) {
+	recorded := make(chan codes.Code, 2)
+	g := NewGrpc(WithMetricsRecorder(recorderFunc(func(code codes.Code) { recorded <- code })))
+	log := &callLogger{messages: make(chan string, 2)}
+	g.logger = log
+
+	hc := startServer(t, g, &panicServer{})
+
+	_, err := hc.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	require.NoError(t, err)
+	require.Equal(t, "call", <-log.messages)
+	require.Equal(t, codes.OK, <-recorded)
+
+	_, err = hc.Watch(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	require.NoError(t, err)
+	require.Equal(t, "stream error", <-log.messages)
+	require.Equal(t, codes.Unimplemented, <-recorded)
+
+	stats := g.Stats()
+	require.Equal(t, uint64(1), stats[checkMethod].Calls)
+	require.Equal(t, uint64(0), stats[checkMethod].Errors)
+	require.Equal(t, uint64(1), stats["/grpc.health.v1.Health/Watch"].Codes[codes.Unimplemented])
+}
+
+// recorderFunc records the codes of the calls
+type recorderFunc func(code codes.Code)
+
+func (f recorderFunc) RecordCall(ctx context.Context, method string, code codes.Code, duration time.Duration) {
+	f(code)
+}
+
+func TestTracing(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+
+	hc := startServer(t, NewGrpc(), &panicServer{})
+
+	// The span of the client is propagated in the metadata
+	traceID := trace.TraceID{1, 2, 3}
+	parent := trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: trace.SpanID{1}, TraceFlags: trace.FlagsSampled})
+	md := metadata.MD{}
+	propagation.TraceContext{}.Inject(trace.ContextWithSpanContext(context.Background(), parent), metadataCarrier{md})
+
+	_, err := hc.Check(metadata.NewOutgoingContext(context.Background(), md), &grpc_health_v1.HealthCheckRequest{})
+	require.NoError(t, err)
+
+	ended := spans.Ended()
+	require.Len(t, ended, 1)
+	require.Equal(t, "grpc.health.v1.Health/Check", ended[0].Name())
+	require.Equal(t, trace.SpanKindServer, ended[0].SpanKind())
+	require.Equal(t, traceID, ended[0].SpanContext().TraceID())
+	require.Equal(t, parent.SpanID(), ended[0].Parent().SpanID())
+}
+
+func TestValidation(t *testing.T) {
+	interceptor := ValidationUnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/books.Books/CreateBook"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return req, nil
+	}
+
+	_, err := interceptor(context.Background(), &book{}, info, handler)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.Equal(t, "title must not be empty", status.Convert(err).Message())
+
+	resp, err := interceptor(context.Background(), &book{title: "Dune"}, info, handler)
+	require.NoError(t, err)
+	require.Equal(t, &book{title: "Dune"}, resp)
+}
+
+func TestInterceptorsToggle(t *testing.T) {
+	g := NewGrpc(WithTracing(false), WithLogging(false), WithRecovery(false), WithValidation(false), WithMetrics(false))
+	unary, stream := g.interceptors()
+
+	// Only the error translation and the method options are left
+	require.Len(t, unary, 2)
+	require.Len(t, stream, 2)
+
+	custom := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return nil, fmt.Errorf("custom")
+	}
+	g = NewGrpc(WithUnaryInterceptors(custom))
+	unary, _ = g.interceptors()
+	require.Len(t, unary, 8)
+}
+
+func TestWithGrpcServerOptions(t *testing.T) {
+	g := NewGrpc(
+		WithGrpcServerOptions(grpc.MaxRecvMsgSize(1024)),
+		WithGrpcServerOptions(grpc.MaxSendMsgSize(1024)),
+	)
+	require.Len(t, g.ServerOptions, 2)
+}
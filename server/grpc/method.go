@@ -0,0 +1,148 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// HEADER_AUTHORIZATION is the metadata key of the credentials of the calls
+	HEADER_AUTHORIZATION = "authorization"
+)
+
+// MethodOptions holds the options of a method, enforced by the server interceptors.
+// The generated servers set them from the method options of the proto definitions.
+type MethodOptions struct {
+	// Timeout is the deadline of the calls without a shorter one, 0 disables it
+	Timeout time.Duration
+	// AuthRequired rejects the calls without credentials
+	AuthRequired bool
+}
+
+// Authenticator verifies the credentials of the calls requiring authentication.
+// The context returned is passed to the handler, e.g. with the authenticated user.
+type Authenticator func(ctx context.Context, token string) (context.Context, error)
+
+// methods holds the options of the methods, by full method name
+type methods struct {
+	sync.RWMutex
+	options map[string]MethodOptions
+}
+
+// WithAuthenticator sets the authenticator of the calls to the methods requiring authentication.
+// Without it, the presence of the credentials is only checked.
+func WithAuthenticator(authenticator Authenticator) Option {
+	return func(g *Grpc) {
+		g.authenticator = authenticator
+	}
+}
+
+// SetMethodOptions sets the options of the method using its full name, e.g. "/pkg.Service/Method"
+func (g *Grpc) SetMethodOptions(method string, opts MethodOptions) {
+	g.methods.Lock()
+	defer g.methods.Unlock()
+
+	g.methods.options[method] = opts
+}
+
+// MethodOptions returns the options of the method
+func (g *Grpc) MethodOptions(method string) (MethodOptions, bool) {
+	g.methods.RLock()
+	defer g.methods.RUnlock()
+
+	opts, ok := g.methods.options[method]
+	return opts, ok
+}
+
+// authenticate returns the context of the call after checking its credentials
+func (g *Grpc) authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(HEADER_AUTHORIZATION)
+	if len(values) == 0 || strings.TrimSpace(values[0]) == "" {
+		return ctx, status.Error(codes.Unauthenticated, "missing credentials")
+	}
+
+	if g.authenticator == nil {
+		return ctx, nil
+	}
+
+	ctx, err := g.authenticator(ctx, values[0])
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return ctx, err
+		}
+
+		return ctx, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	return ctx, nil
+}
+
+// applyMethodOptions returns the context of the call after enforcing the options of the method,
+// and the function releasing its deadline
+func (g *Grpc) applyMethodOptions(ctx context.Context, method string) (context.Context, context.CancelFunc, error) {
+	opts, ok := g.MethodOptions(method)
+	if !ok {
+		return ctx, func() {}, nil
+	}
+
+	if opts.AuthRequired {
+		var err error
+		if ctx, err = g.authenticate(ctx); err != nil {
+			return ctx, func() {}, err
+		}
+	}
+
+	if opts.Timeout > 0 {
+		if deadline, ok := ctx.Deadline(); !ok || time.Until(deadline) > opts.Timeout {
+			ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+			return ctx, cancel, nil
+		}
+	}
+
+	return ctx, func() {}, nil
+}
+
+// UnaryMethodOptionsInterceptor enforces the method options on the unary calls
+func (g *Grpc) UnaryMethodOptionsInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, cancel, err := g.applyMethodOptions(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		defer cancel()
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamMethodOptionsInterceptor enforces the method options on the streams
+func (g *Grpc) StreamMethodOptionsInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, cancel, err := g.applyMethodOptions(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		defer cancel()
+
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// serverStream overrides the context of the stream
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context of the stream
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,94 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const checkMethod = "/grpc.health.v1.Health/Check"
+
+// deadlineServer reports the deadline of the calls on deadlines
+type deadlineServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+	deadlines chan time.Duration
+}
+
+func (s *deadlineServer) Check(ctx context.Context, req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	deadline, _ := ctx.Deadline()
+	s.deadlines <- time.Until(deadline)
+	return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
+}
+
+// startServer serves g and returns the health client connected to it
+func startServer(t *testing.T, g *Grpc, srv grpc_health_v1.HealthServer) grpc_health_v1.HealthClient {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	grpc_health_v1.RegisterHealthServer(g.Server, srv)
+	go g.Server.Serve(lis)
+	t.Cleanup(g.Server.Stop)
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return grpc_health_v1.NewHealthClient(conn)
+}
+
+func TestMethodOptions(t *testing.T) {
+	g := NewGrpc(WithAuthenticator(func(ctx context.Context, token string) (context.Context, error) {
+		if token != "Bearer token" {
+			return ctx, errors.New("invalid token")
+		}
+
+		return ctx, nil
+	}))
+	g.SetMethodOptions(checkMethod, MethodOptions{Timeout: time.Second, AuthRequired: true})
+
+	srv := &deadlineServer{deadlines: make(chan time.Duration, 1)}
+	hc := startServer(t, g, srv)
+	req := &grpc_health_v1.HealthCheckRequest{}
+
+	_, err := hc.Check(context.Background(), req)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), HEADER_AUTHORIZATION, "Bearer invalid")
+	_, err = hc.Check(ctx, req)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// The calls without a shorter deadline get the method timeout
+	ctx = metadata.AppendToOutgoingContext(context.Background(), HEADER_AUTHORIZATION, "Bearer token")
+	_, err = hc.Check(ctx, req)
+	require.NoError(t, err)
+	require.InDelta(t, time.Second, <-srv.deadlines, float64(100*time.Millisecond))
+
+	ctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	_, err = hc.Check(ctx, req)
+	require.NoError(t, err)
+	require.Less(t, <-srv.deadlines, 200*time.Millisecond)
+}
+
+func TestMethodOptionsUnset(t *testing.T) {
+	g := NewGrpc()
+
+	srv := &deadlineServer{deadlines: make(chan time.Duration, 1)}
+	hc := startServer(t, g, srv)
+
+	_, err := hc.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	require.NoError(t, err)
+	<-srv.deadlines
+
+	_, ok := g.MethodOptions(checkMethod)
+	require.False(t, ok)
+}
@@ -0,0 +1,109 @@
+package grpc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MetricsRecorder records the calls handled by the server, e.g. to export them to a metrics backend
+type MetricsRecorder interface {
+	RecordCall(ctx context.Context, method string, code codes.Code, duration time.Duration)
+}
+
+// MethodStats holds the stats of the calls of a method
+type MethodStats struct {
+	// Calls is the number of calls handled
+	Calls uint64
+	// Errors is the number of calls failed
+	Errors uint64
+	// Codes is the number of calls by status code
+	Codes map[codes.Code]uint64
+	// Duration is the total duration of the calls
+	Duration time.Duration
+}
+
+// stats records the stats of the calls by full method name
+type stats struct {
+	sync.Mutex
+	methods map[string]*MethodStats
+}
+
+// RecordCall adds the call to the stats of the method
+func (s *stats) RecordCall(ctx context.Context, method string, code codes.Code, duration time.Duration) {
+	s.Lock()
+	defer s.Unlock()
+
+	m, ok := s.methods[method]
+	if !ok {
+		m = &MethodStats{Codes: map[codes.Code]uint64{}}
+		s.methods[method] = m
+	}
+
+	m.Calls++
+	if code != codes.OK {
+		m.Errors++
+	}
+
+	m.Codes[code]++
+	m.Duration += duration
+}
+
+// WithMetricsRecorder adds a recorder of the calls, along with the stats returned by Stats
+func WithMetricsRecorder(recorder MetricsRecorder) Option {
+	return func(g *Grpc) {
+		g.recorders = append(g.recorders, recorder)
+	}
+}
+
+// Stats returns the stats of the calls by full method name
+func (g *Grpc) Stats() map[string]MethodStats {
+	g.stats.Lock()
+	defer g.stats.Unlock()
+
+	methods := make(map[string]MethodStats, len(g.stats.methods))
+	for method, m := range g.stats.methods {
+		s := *m
+		s.Codes = make(map[codes.Code]uint64, len(m.Codes))
+		for code, n := range m.Codes {
+			s.Codes[code] = n
+		}
+
+		methods[method] = s
+	}
+
+	return methods
+}
+
+// record records the call to the stats and the recorders
+func (g *Grpc) record(ctx context.Context, method string, start time.Time, err error) {
+	code, duration := status.Code(err), time.Since(start)
+	g.stats.RecordCall(ctx, method, code, duration)
+	for _, recorder := range g.recorders {
+		recorder.RecordCall(ctx, method, code, duration)
+	}
+}
+
+// MetricsUnaryServerInterceptor records the calls
+func (g *Grpc) MetricsUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		g.record(ctx, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// MetricsStreamServerInterceptor records the streams
+func (g *Grpc) MetricsStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		g.record(ss.Context(), info.FullMethod, start, err)
+		return err
+	}
+}
@@ -0,0 +1,30 @@
+package rest
+
+import (
+	"errors"
+
+	gs_errors "github.com/easeq/go-service/errors"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ErrorHandler writes the errors returned by the fiber handlers as JSON errors.Response bodies.
+// The fiber errors keep their HTTP status code, the internal errors are masked.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	var httpStatus int
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		httpStatus = fiberErr.Code
+		err = gs_errors.New(gs_errors.CodeFromHTTPStatus(fiberErr.Code), fiberErr.Message)
+	}
+
+	e := gs_errors.FromError(err)
+	if httpStatus == 0 {
+		httpStatus = e.HTTPStatus()
+	}
+
+	if retryAfter := e.RetryAfterHeader(); retryAfter != "" {
+		c.Set(gs_errors.HEADER_RETRY_AFTER, retryAfter)
+	}
+
+	return c.Status(httpStatus).JSON(e.Response())
+}
@@ -0,0 +1,70 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	gs_errors "github.com/easeq/go-service/errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+func TestErrorHandler(t *testing.T) {
+	r := NewRest()
+	r.App.Get("/books/:name", func(c *fiber.Ctx) error {
+		switch c.Params("name") {
+		case "limited":
+			return gs_errors.RateLimited("too many requests", 2*time.Second)
+		case "internal":
+			return errors.New("dial tcp 10.0.0.3:5432: connection refused")
+		}
+
+		return gs_errors.NotFound("book not found")
+	})
+
+	tests := []struct {
+		path       string
+		httpStatus int
+		code       codes.Code
+		message    string
+	}{
+		{"/books/missing", http.StatusNotFound, codes.NotFound, "book not found"},
+		{"/books/limited", http.StatusTooManyRequests, codes.ResourceExhausted, "too many requests"},
+		{"/books/internal", http.StatusInternalServerError, codes.Internal, gs_errors.INTERNAL_MESSAGE},
+		{"/shelves", http.StatusNotFound, codes.NotFound, "Cannot GET /shelves"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			res, err := r.App.Test(httptest.NewRequest(http.MethodGet, tt.path, nil))
+			require.NoError(t, err)
+			require.Equal(t, tt.httpStatus, res.StatusCode)
+
+			body := gs_errors.Response{}
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+			require.Equal(t, int32(tt.code), body.Code)
+			require.Equal(t, tt.message, body.Message)
+		})
+	}
+
+	res, err := r.App.Test(httptest.NewRequest(http.MethodGet, "/books/limited", nil))
+	require.NoError(t, err)
+	require.Equal(t, "2", res.Header.Get(gs_errors.HEADER_RETRY_AFTER))
+}
+
+func TestErrorHandlerOverride(t *testing.T) {
+	r := NewRest(WithOptions(fiber.Config{
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			return c.SendStatus(http.StatusTeapot)
+		},
+	}))
+
+	res, err := r.App.Test(httptest.NewRequest(http.MethodGet, "/missing", nil))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusTeapot, res.StatusCode)
+}
@@ -54,7 +54,17 @@ func NewRest(opts ...Option) *Rest {
 		opt(r)
 	}
 
-	r.App = fiber.New(r.Options...)
+	// Only the first config is used by fiber, the default error handler is set unless it has one
+	config := fiber.Config{}
+	if len(r.Options) > 0 {
+		config = r.Options[0]
+	}
+
+	if config.ErrorHandler == nil {
+		config.ErrorHandler = ErrorHandler
+	}
+
+	r.App = fiber.New(config)
 
 	r.i = NewInitializer(r)
 	return r
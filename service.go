@@ -48,6 +48,14 @@ func init() {
 
 }
 
+// WithComponent adds a service component by key.
+// It can be used to add components that do not have a dedicated option
+func WithComponent(key string, comp component.Component) ServiceOption {
+	return func(s *Service) {
+		s.components[key] = comp
+	}
+}
+
 // WithServer passes the server
 func WithServer(srv server.Server) ServiceOption {
 	return func(s *Service) {
@@ -1,20 +1,15 @@
 package utils
 
 import (
-	"database/sql"
-	"errors"
 	"sync"
 
+	gs_errors "github.com/easeq/go-service/errors"
 	"google.golang.org/grpc/codes"
 )
 
-// GetErrorCode returns the error code to use
+// GetErrorCode returns the gRPC code of the error, see errors.Code
 func GetErrorCode(err error) codes.Code {
-	if errors.Is(err, sql.ErrNoRows) {
-		return codes.NotFound
-	}
-
-	return codes.Internal
+	return gs_errors.Code(err)
 }
 
 // MergeErrors merges multiple channels of errors.